@@ -0,0 +1,23 @@
+//go:build linux
+
+package cmd
+
+import (
+	"io/fs"
+	"syscall"
+	"time"
+)
+
+// accessTime returns the last access time of a file, or its modification time if that is more recent, because
+// file systems mounted with relatime or noatime do not update the access time on every read.
+func accessTime(info fs.FileInfo) time.Time {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return info.ModTime()
+	}
+	atime := time.Unix(stat.Atim.Sec, stat.Atim.Nsec)
+	if atime.Before(info.ModTime()) {
+		return info.ModTime()
+	}
+	return atime
+}
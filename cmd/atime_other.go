@@ -0,0 +1,13 @@
+//go:build !linux && !darwin
+
+package cmd
+
+import (
+	"io/fs"
+	"time"
+)
+
+// accessTime returns the modification time of a file, because the access time is not available on this platform.
+func accessTime(info fs.FileInfo) time.Time {
+	return info.ModTime()
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/config"
+)
+
+// configCmd represents the config command
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect and change the menv configuration",
+	Long: `With this command you can inspect and change the menv configuration file.
+
+The configuration file is located at ~/.config/menv/config.yaml, which can be overridden with the MENV_CONFIG
+environment variable. Every setting is resolved in the following order: environment variable, configuration file,
+default value.
+
+Available settings:
+` + config.Usage(),
+}
+
+var configGetCmd = &cobra.Command{
+	Use:       "get [key]",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: config.Keys(),
+	Short:     "Print the effective value of a setting",
+	Run: func(cmd *cobra.Command, args []string) {
+		value, err := config.Value(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:       "set [key] [value]",
+	Args:      cobra.ExactArgs(2),
+	ValidArgs: config.Keys(),
+	Short:     "Store a setting in the configuration file",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := config.SetValue(args[0], args[1])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Set %v to %v in %v\n", args[0], args[1], config.File())
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Args:    cobra.NoArgs,
+	Short:   "List all settings with their effective value and source",
+	Run: func(cmd *cobra.Command, args []string) {
+		printConfig()
+	},
+}
+
+func printConfig() {
+	fmt.Printf("Configuration file: %v\n", config.File())
+	for _, key := range config.Keys() {
+		value, _ := config.Value(key)
+		fmt.Printf("  %v = %v (%v)\n", key, value, config.Source(key))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configListCmd)
+}
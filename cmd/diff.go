@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/color"
+	"menv/profiles"
+	"os"
+)
+
+// diffCmd represents the diff command
+var diffCmd = &cobra.Command{
+	Use:   "diff [profile] [profile]",
+	Args:  cobra.ExactArgs(2),
+	Short: "Show the differences between two profiles",
+	Long: `This command compares the settings.xml and MAVEN_OPTS of two profiles. Instead of comparing the files line by
+line, servers, mirrors, proxies and profiles are compared by their id, and activeProfiles and pluginGroups by value,
+so the order and formatting of the files do not matter. Profiles are compared including the settings of the profiles
+they extend. MAVEN_OPTS are not inherited from the profiles a profile extends, so only the MAVEN_OPTS of the two
+profiles are compared. Passwords, passphrases and -D options named like a password, secret or token are masked.
+Encrypted profiles are decrypted for the comparison.`,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) > 1 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return profiles.Profiles(), cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		lock, err := unlockProfiles(args, true, os.Stdin)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		defer func() {
+			if err := lock(); err != nil {
+				fmt.Println(err)
+			}
+		}()
+
+		err = printDiff(args[0], args[1])
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func printDiff(a string, b string) error {
+	changes, err := profiles.Diff(a, b)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Printf("Profiles %v and %v are equal\n", a, b)
+		printInheritedOptsNote(a, b)
+		return nil
+	}
+
+	fmt.Println(color.Format(color.RED, "--- "+a))
+	fmt.Println(color.Format(color.GREEN, "+++ "+b))
+
+	section := ""
+	for _, change := range changes {
+		if change.Section != section {
+			section = change.Section
+			fmt.Printf("%v:\n", section)
+		}
+
+		switch change.Kind {
+		case profiles.Added:
+			fmt.Println(color.Format(color.GREEN, "  + "+change.Id))
+		case profiles.Removed:
+			fmt.Println(color.Format(color.RED, "  - "+change.Id))
+		case profiles.Changed:
+			indent := "  "
+			if change.Id != "" {
+				fmt.Println(color.Format(color.YELLOW, "  ~ "+change.Id))
+				indent = "      "
+			}
+			for _, field := range change.Fields {
+				fmt.Println(indent + field)
+			}
+		}
+	}
+	printInheritedOptsNote(a, b)
+	return nil
+}
+
+// printInheritedOptsNote points out that MAVEN_OPTS, unlike the settings, are not inherited from extended profiles.
+func printInheritedOptsNote(a string, b string) {
+	if profiles.Parent(a) != "" || profiles.Parent(b) != "" {
+		fmt.Println("MAVEN_OPTS are not inherited from extended profiles and are compared as set on each profile")
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
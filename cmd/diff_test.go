@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"menv/color"
+	"menv/profiles"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestPrintDiff(t *testing.T) {
+	initMvnTest(t)
+	color.SetEnabled(false)
+	defer color.SetEnabled(true)
+	_ = profiles.Create("test")
+	_ = profiles.Create("prod")
+	_ = os.WriteFile(profiles.File("test"), []byte("<settings><servers><server><id>nexus</id><password>a</password></server></servers></settings>"), 0644)
+	_ = os.WriteFile(profiles.File("prod"), []byte("<settings><servers><server><id>nexus</id><password>b</password></server><server><id>releases</id></server></servers></settings>"), 0644)
+	_ = profiles.SetMvnOpts("prod", "-Xmx2g")
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_ = printDiff("test", "prod")
+	_ = printDiff("test", "test")
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, output, "--- test\n+++ prod\nservers:\n  ~ nexus\n      password: ****** -> ******\n  + releases\nMAVEN_OPTS:\n  + -Xmx2g\n")
+	assert.NotContains(t, output, "<password>")
+	assert.Contains(t, output, "Profiles test and test are equal\n")
+}
+
+func TestPrintDiffInheritedOpts(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("base")
+	_ = profiles.Create("test")
+	_ = profiles.SetMvnOpts("base", "-Xmx2g")
+	_ = profiles.Extend("test", "base")
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_ = printDiff("base", "test")
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, output, "MAVEN_OPTS:\n")
+	assert.True(t, strings.HasSuffix(output, "MAVEN_OPTS are not inherited from extended profiles and are compared as set on each profile\n"))
+}
@@ -0,0 +1,331 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"menv/config"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mvnResolver finds maven installations and returns the paths to their mvn binaries.
+type mvnResolver func(shell func(string, ...string) profiles.ShellCommand) ([]string, error)
+
+// mavenInstallation is a maven installation found by one of the mvnResolvers.
+type mavenInstallation struct {
+	Version string
+	Path    string
+	Source  string
+}
+
+// mvnResolvers contains the known maven discovery methods, which are consulted in the order configured by
+// config.Discovery.
+var mvnResolvers = map[string]mvnResolver{
+	"homebrew":   findMvnInCellar,
+	"maven_home": findMvnInMavenHome,
+	"path":       findMvnOnPath,
+	"sdkman":     findMvnInSdkman,
+	"system":     findMvnInSystem,
+}
+
+// systemMavenLocations are the well known locations of a maven installation on Linux.
+var systemMavenLocations = []string{"/usr/share/maven", "/opt/maven", "/opt/*maven*"}
+
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)+(-[0-9A-Za-z.-]+)?`)
+
+func findMvnInCellar(shell func(string, ...string) profiles.ShellCommand) ([]string, error) {
+	cmd, err := shell("brew", "--cellar").Output()
+	if err != nil {
+		return nil, errors.New("could not find maven in (home)brew cellar")
+	}
+	cellar := string(cmd)
+	cellar = strings.ReplaceAll(cellar, "\n", "")
+	cellar = strings.ReplaceAll(cellar, "\r", "")
+	cellar = filepath.Join(cellar, "maven")
+
+	if _, err := os.Stat(cellar); os.IsNotExist(err) {
+		return nil, errors.New("could not find maven in (home)brew cellar")
+	}
+
+	mvns := make([]string, 0)
+
+	_ = filepath.WalkDir(cellar, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && d.Name() == "mvn" && !strings.Contains(path, "libexec") {
+			mvns = append(mvns, path)
+		}
+		return nil
+	})
+
+	if len(mvns) == 0 {
+		return nil, errors.New("could not find maven in (home)brew cellar")
+	}
+
+	return mvns, nil
+}
+
+func findMvnInMavenHome(_ func(string, ...string) profiles.ShellCommand) ([]string, error) {
+	mvns := make([]string, 0)
+	for _, env := range []string{"MAVEN_HOME", "M2_HOME"} {
+		home, b := os.LookupEnv(env)
+		if !b || home == "" {
+			continue
+		}
+		mvn := filepath.Join(home, "bin", "mvn")
+		if isExecutable(mvn) {
+			mvns = append(mvns, mvn)
+		}
+	}
+
+	if len(mvns) == 0 {
+		return nil, errors.New("could not find maven in MAVEN_HOME or M2_HOME")
+	}
+	return mvns, nil
+}
+
+func findMvnOnPath(_ func(string, ...string) profiles.ShellCommand) ([]string, error) {
+	mvns := make([]string, 0)
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		mvn := filepath.Join(dir, "mvn")
+		if isExecutable(mvn) && !isMenvShim(mvn) {
+			mvns = append(mvns, mvn)
+		}
+	}
+
+	if len(mvns) == 0 {
+		return nil, errors.New("could not find maven on PATH")
+	}
+	return mvns, nil
+}
+
+func findMvnInSdkman(_ func(string, ...string) profiles.ShellCommand) ([]string, error) {
+	candidates := filepath.Join(sdkmanDir(), "candidates", "maven")
+	mvns := make([]string, 0)
+
+	current := filepath.Join(candidates, "current", "bin", "mvn")
+	if isExecutable(current) {
+		mvns = append(mvns, current)
+	}
+
+	matches, _ := filepath.Glob(filepath.Join(candidates, "*", "bin", "mvn"))
+	for _, mvn := range matches {
+		if mvn != current && isExecutable(mvn) {
+			mvns = append(mvns, mvn)
+		}
+	}
+
+	if len(mvns) == 0 {
+		return nil, errors.New("could not find maven in SDKMAN")
+	}
+	return mvns, nil
+}
+
+func findMvnInSystem(_ func(string, ...string) profiles.ShellCommand) ([]string, error) {
+	mvns := make([]string, 0)
+	for _, location := range systemMavenLocations {
+		matches, _ := filepath.Glob(filepath.Join(location, "bin", "mvn"))
+		for _, mvn := range matches {
+			if isExecutable(mvn) {
+				mvns = append(mvns, mvn)
+			}
+		}
+	}
+
+	if len(mvns) == 0 {
+		return nil, errors.New("could not find maven in " + strings.Join(systemMavenLocations, ", "))
+	}
+	return mvns, nil
+}
+
+func sdkmanDir() string {
+	dir, b := os.LookupEnv("SDKMAN_DIR")
+	if b && dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".sdkman")
+}
+
+func isExecutable(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	return !info.IsDir() && info.Mode()&0111 != 0
+}
+
+// isMenvShim reports whether the given mvn is the menv shim, which would call menv again instead of maven.
+func isMenvShim(path string) bool {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false
+	}
+
+	if self, err := os.Executable(); err == nil {
+		if resolvedSelf, err := filepath.EvalSymlinks(self); err == nil && resolvedSelf == resolved {
+			return true
+		}
+	}
+
+	info, err := os.Stat(resolved)
+	if err != nil || info.Size() > 4096 {
+		return false
+	}
+	data, _ := os.ReadFile(resolved)
+	return strings.Contains(string(data), "menv mvn")
+}
+
+// discoverMavenInstallations returns the maven installations found by the configured discovery methods. The
+// installations of every method are sorted by version, newest first, and the methods keep their configured order.
+func discoverMavenInstallations(shell func(string, ...string) profiles.ShellCommand) ([]mavenInstallation, []error) {
+	installations := make([]mavenInstallation, 0)
+	failures := make([]error, 0)
+	seen := make(map[string]bool)
+
+	for _, method := range config.Discovery() {
+		resolver, ok := mvnResolvers[method]
+		if !ok {
+			failures = append(failures, errors.New(fmt.Sprintf("unknown maven discovery method %v", method)))
+			continue
+		}
+
+		mvns, err := resolver(shell)
+		if err != nil {
+			failures = append(failures, err)
+			continue
+		}
+
+		found := make([]mavenInstallation, 0)
+		for _, mvn := range mvns {
+			resolved, err := filepath.EvalSymlinks(mvn)
+			if err != nil {
+				resolved = mvn
+			}
+			if seen[resolved] {
+				continue
+			}
+			seen[resolved] = true
+			found = append(found, mavenInstallation{Version: mavenVersion(mvn), Path: mvn, Source: method})
+		}
+
+		sort.SliceStable(found, func(i, j int) bool {
+			return compareVersions(found[i].Version, found[j].Version) > 0
+		})
+		installations = append(installations, found...)
+	}
+
+	return installations, failures
+}
+
+// discoverMaven returns the mvn binary of the maven installation matching the given version. Without a version the
+// newest installation of the first discovery method that finds one is returned.
+func discoverMaven(shell func(string, ...string) profiles.ShellCommand, version string) (string, error) {
+	methods := config.Discovery()
+	if len(methods) == 0 {
+		return "", errors.New("no maven discovery methods configured")
+	}
+	for _, method := range methods {
+		if _, ok := mvnResolvers[method]; !ok {
+			return "", errors.New(fmt.Sprintf("unknown maven discovery method %v", method))
+		}
+	}
+
+	installations, failures := discoverMavenInstallations(shell)
+
+	if version != "" {
+		for _, installation := range installations {
+			if matchesVersion(installation.Version, version) {
+				return installation.Path, nil
+			}
+		}
+		return "", errors.New(fmt.Sprintf("maven version %v is not installed, run 'menv maven ls' to list the installed versions", version))
+	}
+
+	if len(installations) > 0 {
+		return installations[0].Path, nil
+	}
+
+	if len(failures) == 1 {
+		return "", failures[0]
+	}
+	messages := make([]string, 0, len(failures))
+	for _, failure := range failures {
+		messages = append(messages, failure.Error())
+	}
+	return "", errors.New("could not find maven:\n  " + strings.Join(messages, "\n  "))
+}
+
+// mavenVersion determines the version of the maven installation the given mvn binary belongs to, using the
+// maven-core jar of the installation or, if that cannot be found, the path of the installation.
+func mavenVersion(mvn string) string {
+	resolved, err := filepath.EvalSymlinks(mvn)
+	if err != nil {
+		resolved = mvn
+	}
+	home := filepath.Dir(filepath.Dir(resolved))
+
+	for _, lib := range []string{filepath.Join(home, "lib"), filepath.Join(home, "libexec", "lib")} {
+		jars, _ := filepath.Glob(filepath.Join(lib, "maven-core-*.jar"))
+		for _, jar := range jars {
+			version := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(jar), "maven-core-"), ".jar")
+			if versionPattern.MatchString(version) {
+				return version
+			}
+		}
+	}
+
+	elements := strings.Split(filepath.ToSlash(home), "/")
+	for i := len(elements) - 1; i >= 0; i-- {
+		if version := versionPattern.FindString(elements[i]); version != "" {
+			return version
+		}
+	}
+	return "unknown"
+}
+
+// compareVersions compares two maven versions semantically. A version with a qualifier, like 4.0.0-rc-2, is older
+// than the same version without one.
+func compareVersions(a string, b string) int {
+	aNumbers, aQualifier, _ := strings.Cut(a, "-")
+	bNumbers, bQualifier, _ := strings.Cut(b, "-")
+	aParts := strings.Split(aNumbers, ".")
+	bParts := strings.Split(bNumbers, ".")
+
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		var x, y int
+		if i < len(aParts) {
+			x, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			y, _ = strconv.Atoi(bParts[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+
+	switch {
+	case aQualifier == bQualifier:
+		return 0
+	case aQualifier == "":
+		return 1
+	case bQualifier == "":
+		return -1
+	default:
+		return strings.Compare(aQualifier, bQualifier)
+	}
+}
+
+// matchesVersion reports whether the installed version matches the requested version. A requested version can be
+// a prefix of the installed version, so 3.9 matches 3.9.6.
+func matchesVersion(installed string, requested string) bool {
+	return installed == requested || strings.HasPrefix(installed, requested+".")
+}
@@ -0,0 +1,259 @@
+package cmd
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"menv/config"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createMvn(t *testing.T, dir string, content string) string {
+	t.Helper()
+	_ = os.MkdirAll(dir, 0755)
+	mvn := filepath.Join(dir, "mvn")
+	_ = os.WriteFile(mvn, []byte(content), 0755)
+	return mvn
+}
+
+func TestFindMvnInMavenHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MAVEN_HOME", "")
+	t.Setenv("M2_HOME", home)
+
+	_, err := findMvnInMavenHome(nil)
+	assert.EqualError(t, err, "could not find maven in MAVEN_HOME or M2_HOME")
+
+	expected := createMvn(t, filepath.Join(home, "bin"), "#!/bin/sh\n")
+	actual, err := findMvnInMavenHome(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{expected}, actual)
+}
+
+func TestFindMvnOnPathSkipsShim(t *testing.T) {
+	shimDir := t.TempDir()
+	mavenDir := t.TempDir()
+	createMvn(t, shimDir, "#!/usr/bin/env bash\nmenv mvn \"$@\"\n")
+	t.Setenv("PATH", shimDir)
+
+	_, err := findMvnOnPath(nil)
+	assert.EqualError(t, err, "could not find maven on PATH")
+
+	expected := createMvn(t, mavenDir, "#!/bin/sh\nexec java -classpath ...\n")
+	t.Setenv("PATH", shimDir+string(os.PathListSeparator)+mavenDir)
+	actual, err := findMvnOnPath(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{expected}, actual)
+}
+
+func TestFindMvnOnPathNotExecutable(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "mvn"), []byte("#!/bin/sh\n"), 0644)
+	t.Setenv("PATH", dir)
+
+	_, err := findMvnOnPath(nil)
+	assert.Error(t, err)
+}
+
+func TestFindMvnInSdkman(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("SDKMAN_DIR", dir)
+
+	_, err := findMvnInSdkman(nil)
+	assert.EqualError(t, err, "could not find maven in SDKMAN")
+
+	versioned := createMvn(t, filepath.Join(dir, "candidates", "maven", "3.9.6", "bin"), "#!/bin/sh\n")
+	actual, _ := findMvnInSdkman(nil)
+	assert.Equal(t, []string{versioned}, actual)
+
+	current := createMvn(t, filepath.Join(dir, "candidates", "maven", "current", "bin"), "#!/bin/sh\n")
+	actual, _ = findMvnInSdkman(nil)
+	assert.Equal(t, []string{current, versioned}, actual)
+}
+
+func TestFindMvnInSystem(t *testing.T) {
+	dir := t.TempDir()
+	original := systemMavenLocations
+	systemMavenLocations = []string{filepath.Join(dir, "*maven*")}
+	defer func() { systemMavenLocations = original }()
+
+	_, err := findMvnInSystem(nil)
+	assert.Error(t, err)
+
+	expected := createMvn(t, filepath.Join(dir, "apache-maven-3.9.6", "bin"), "#!/bin/sh\n")
+	actual, err := findMvnInSystem(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{expected}, actual)
+}
+
+func TestDiscoverMavenOrder(t *testing.T) {
+	initMvnTest(t)
+	home := t.TempDir()
+	expected := createMvn(t, filepath.Join(home, "bin"), "#!/bin/sh\n")
+	t.Setenv("MAVEN_HOME", home)
+	t.Setenv("MENV_DISCOVERY", "homebrew,maven_home")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) profiles.ShellCommand {
+		return &mockShell
+	}
+
+	mockShell.On("Output").Return([]byte(""), errors.New("brew: command not found"))
+
+	actual, err := discoverMaven(mockProvider, "")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+	mockShell.AssertExpectations(t)
+}
+
+func TestDiscoverMavenFailures(t *testing.T) {
+	initMvnTest(t)
+	t.Setenv("MAVEN_HOME", "")
+	t.Setenv("M2_HOME", "")
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("MENV_DISCOVERY", "maven_home,path")
+
+	_, err := discoverMaven(nil, "")
+	assert.EqualError(t, err, "could not find maven:\n  could not find maven in MAVEN_HOME or M2_HOME\n  could not find maven on PATH")
+
+	t.Setenv("MENV_DISCOVERY", "unknown")
+	_, err = discoverMaven(nil, "")
+	assert.EqualError(t, err, "unknown maven discovery method unknown")
+
+	config.Set(config.Config{})
+	t.Setenv("MENV_DISCOVERY", "")
+	_, err = discoverMaven(nil, "")
+	assert.EqualError(t, err, "no maven discovery methods configured")
+}
+
+func createCellar(t *testing.T, versions ...string) string {
+	t.Helper()
+	cellar := t.TempDir()
+	for _, version := range versions {
+		home := filepath.Join(cellar, "maven", version)
+		createMvn(t, filepath.Join(home, "bin"), "#!/bin/sh\n")
+		createMvn(t, filepath.Join(home, "libexec", "bin"), "#!/bin/sh\n")
+	}
+	return cellar
+}
+
+func TestDiscoverMavenInstallationsSorted(t *testing.T) {
+	initMvnTest(t)
+	cellar := createCellar(t, "3.9.6", "3.10.0", "3.8.8", "4.0.0-rc-2")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) profiles.ShellCommand {
+		return &mockShell
+	}
+
+	mockShell.On("Output").Return([]byte(cellar), nil)
+
+	installations, failures := discoverMavenInstallations(mockProvider)
+	assert.Empty(t, failures)
+
+	versions := make([]string, 0)
+	for _, installation := range installations {
+		versions = append(versions, installation.Version)
+		assert.Equal(t, "homebrew", installation.Source)
+	}
+	assert.Equal(t, []string{"4.0.0-rc-2", "3.10.0", "3.9.6", "3.8.8"}, versions)
+}
+
+func TestDiscoverMavenPinnedVersion(t *testing.T) {
+	initMvnTest(t)
+	cellar := createCellar(t, "3.9.6", "3.8.8")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) profiles.ShellCommand {
+		return &mockShell
+	}
+
+	mockShell.On("Output").Return([]byte(cellar), nil)
+
+	actual, err := discoverMaven(mockProvider, "")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(cellar, "maven", "3.9.6", "bin", "mvn"), actual)
+
+	actual, err = discoverMaven(mockProvider, "3.8")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(cellar, "maven", "3.8.8", "bin", "mvn"), actual)
+
+	_, err = discoverMaven(mockProvider, "3.6.3")
+	assert.EqualError(t, err, "maven version 3.6.3 is not installed, run 'menv maven ls' to list the installed versions")
+}
+
+func TestFindMavenHonoursPin(t *testing.T) {
+	initMvnTest(t)
+	cellar := createCellar(t, "3.9.6", "3.8.8")
+	_ = profiles.Create("test")
+	_ = profiles.Set("test")
+	_ = profiles.SetMavenVersion("test", "3.8.8")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) profiles.ShellCommand {
+		return &mockShell
+	}
+
+	mockShell.On("Output").Return([]byte(cellar), nil)
+
+	actual, err := findMaven(mockProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(cellar, "maven", "3.8.8", "bin", "mvn"), actual)
+
+	_ = profiles.PinMavenVersion("3.9.6")
+	actual, err = findMaven(mockProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(cellar, "maven", "3.9.6", "bin", "mvn"), actual)
+}
+
+func TestMavenVersion(t *testing.T) {
+	dir := t.TempDir()
+	mvn := createMvn(t, filepath.Join(dir, "maven", "bin"), "#!/bin/sh\n")
+	assert.Equal(t, "unknown", mavenVersion(mvn))
+
+	_ = os.MkdirAll(filepath.Join(dir, "maven", "lib"), 0755)
+	_ = os.WriteFile(filepath.Join(dir, "maven", "lib", "maven-core-3.9.6.jar"), []byte{}, 0644)
+	assert.Equal(t, "3.9.6", mavenVersion(mvn))
+
+	assert.Equal(t, "3.8.8", mavenVersion(createMvn(t, filepath.Join(dir, "apache-maven-3.8.8", "bin"), "")))
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"3.9.6", "3.9.6", 0},
+		{"3.10.0", "3.9.6", 1},
+		{"3.9", "3.9.1", -1},
+		{"4.0.0-rc-2", "4.0.0", -1},
+		{"4.0.0-rc-2", "3.9.9", 1},
+	}
+
+	for _, test := range tests {
+		actual := compareVersions(test.a, test.b)
+		switch {
+		case test.expected == 0:
+			assert.Zerof(t, actual, "compareVersions(%v, %v)", test.a, test.b)
+		case test.expected > 0:
+			assert.Positivef(t, actual, "compareVersions(%v, %v)", test.a, test.b)
+		default:
+			assert.Negativef(t, actual, "compareVersions(%v, %v)", test.a, test.b)
+		}
+	}
+}
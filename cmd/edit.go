@@ -3,7 +3,10 @@ package cmd
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"menv/color"
 	"menv/profiles"
+	"os"
+	"strings"
 )
 
 // editCmd represents the edit command
@@ -16,6 +19,9 @@ var editCmd = &cobra.Command{
 
 You can change the editor by setting the MENV_EDITOR environment variable.
 
+After the editor exits, the settings.xml is validated. When it is invalid, the problems are shown with their line and
+column, and you can reopen the editor, keep the file, or revert it to the version before editing.
+
 Example:
 export MENV_EDITOR=nano`,
 	Run: func(cmd *cobra.Command, args []string) {
@@ -35,13 +41,48 @@ export MENV_EDITOR=nano`,
 			return
 		}
 
-		err := profiles.Edit(profile, profiles.ExecCmdProvider)
+		lock, err := unlockProfiles([]string{profile}, false, os.Stdin)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		err = profiles.Edit(profile, profiles.ExecCmdProvider, promptInvalidSettings)
+		if lockErr := lock(); err == nil {
+			err = lockErr
+		}
 		if err != nil {
 			fmt.Println(err)
 		}
 	},
 }
 
+// promptInvalidSettings shows the problems found in an edited settings.xml and asks what to do with it.
+func promptInvalidSettings(problems []profiles.ValidationError) profiles.EditAction {
+	fmt.Println(color.Format(color.RED, "The settings.xml is invalid:"))
+	for _, problem := range problems {
+		fmt.Printf("  %v\n", problem)
+	}
+
+	for {
+		var choice string
+		fmt.Print("(r)eopen the editor, (k)eep the file or re(v)ert to the version before editing? ")
+		_, err := fmt.Scanln(&choice)
+		if err != nil && choice == "" {
+			return profiles.EditKeep
+		}
+
+		switch strings.ToLower(choice) {
+		case "r", "reopen":
+			return profiles.EditReopen
+		case "k", "keep":
+			return profiles.EditKeep
+		case "v", "revert":
+			return profiles.EditRevert
+		}
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(editCmd)
 }
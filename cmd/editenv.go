@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+)
+
+// editenvCmd represents the editenv command
+var editenvCmd = &cobra.Command{
+	Use:               "editenv [profile]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Edit the environment variables of the provided profile, or the active profile if none is provided, or prompt for a profile if none is active",
+	Long: `With this command you can edit the environment variables of a profile. By default it will open the file in vi.
+
+The file uses the dotenv syntax. Lines starting with # are comments, values can be quoted and ${VAR} references are
+expanded, except in single quoted values. The variables are only applied to maven, not to your shell.
+
+Example:
+JAVA_HOME=/usr/lib/jvm/java-21-openjdk
+MAVEN_ARGS="-T 1C"
+HTTPS_PROXY=http://proxy.acme.com:8080
+NO_PROXY=localhost,${NO_PROXY}
+
+You can change the editor by setting the MENV_EDITOR environment variable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+
+		profile = resolveProfile(profile)
+		if profile == "" {
+			return
+		}
+
+		err := profiles.EditEnv(profile, profiles.ExecCmdProvider)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(editenvCmd)
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+)
+
+// edittoolchainsCmd represents the edittoolchains command
+var edittoolchainsCmd = &cobra.Command{
+	Use:               "edittoolchains [profile]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Edit toolchains.xml for the provided profile, or the active profile if none is provided, or prompt for a profile if none is active",
+	Long: `With this command you can edit the toolchains.xml of a profile. By default it will open the file in vi.
+
+When the profile has a toolchains.xml, maven is executed with --toolchains pointing to it. Use
+'menv toolchains generate' to create a toolchains.xml from the JDKs installed on this machine.
+
+You can change the editor by setting the MENV_EDITOR environment variable.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var profile string
+		if len(args) > 0 {
+			profile = args[0]
+		}
+
+		profile = resolveProfile(profile)
+		if profile == "" {
+			return
+		}
+
+		err := profiles.EditToolchains(profile, profiles.ExecCmdProvider)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(edittoolchainsCmd)
+}
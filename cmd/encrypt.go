@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"menv/profiles"
+	"os"
+)
+
+var encryptKeyFile string
+
+// encryptCmd represents the encrypt command
+var encryptCmd = &cobra.Command{
+	Use:               "encrypt [profile]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Encrypt all files of the provided profile, or the active profile if none is provided",
+	Long: `This command encrypts the settings.xml, MAVEN_OPTS, environment variables and all other files of a profile with a
+passphrase, or with the content of a key file when --key-file is provided. Only the name of the profile it extends stays
+in clear text. The history of the profile is removed, because it holds its previous settings in clear text.
+
+'menv mvn', 'menv edit' and 'menv diff' decrypt encrypted profiles into a temporary directory only the current user
+can read, which is wiped afterwards. The passphrase is asked for every time, the key file is read from the path it had
+when the profile was encrypted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(firstArg(args))
+		if profile == "" {
+			return
+		}
+
+		err := encryptProfile(profile, encryptKeyFile, os.Stdin)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Encrypted profile %v\n", profile)
+	},
+}
+
+// decryptCmd represents the decrypt command
+var decryptCmd = &cobra.Command{
+	Use:               "decrypt [profile]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Store the files of the provided encrypted profile, or the active profile if none is provided, in clear text again",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(firstArg(args))
+		if profile == "" {
+			return
+		}
+
+		reader := bufio.NewReader(os.Stdin)
+		passphrase, err := profilePassphrase(profile, reader, os.Stdin)
+		if err == nil {
+			err = profiles.Decrypt(profile, passphrase)
+		}
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Decrypted profile %v\n", profile)
+	},
+}
+
+func encryptProfile(profile string, keyFile string, in io.Reader) error {
+	if !profiles.Exists(profile) {
+		return errors.New(fmt.Sprintf("profile %v does not exist", profile))
+	}
+	if profiles.Encrypted(profile) {
+		return errors.New(fmt.Sprintf("profile %v is already encrypted", profile))
+	}
+	if keyFile != "" {
+		return profiles.Encrypt(profile, nil, keyFile)
+	}
+
+	reader := bufio.NewReader(in)
+	passphrase, err := promptSecret(reader, in, "Passphrase: ")
+	if err != nil {
+		return err
+	}
+	confirmation, err := promptSecret(reader, in, "Repeat passphrase: ")
+	if err != nil {
+		return err
+	}
+	if passphrase == "" || passphrase != confirmation {
+		return errors.New("the passphrases are empty or do not match")
+	}
+	return profiles.Encrypt(profile, []byte(passphrase), "")
+}
+
+// profilePassphrase returns the content of the key file the given profile was encrypted with, or asks for its
+// passphrase.
+func profilePassphrase(profile string, reader *bufio.Reader, in io.Reader) ([]byte, error) {
+	if keyFile := profiles.EncryptionKeyFile(profile); keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("could not read key file of profile %v: %v", profile, err))
+		}
+		return data, nil
+	}
+
+	passphrase, err := promptSecret(reader, in, fmt.Sprintf("Passphrase of profile %v: ", profile))
+	return []byte(passphrase), err
+}
+
+// unlockProfiles unlocks the given encrypted profiles and, with chain, the encrypted profiles they extend. The returned
+// function locks them again and returns the first error that occurred.
+func unlockProfiles(names []string, chain bool, in io.Reader) (func() error, error) {
+	locks := make([]func() error, 0)
+	lock := func() error {
+		var result error
+		for i := len(locks) - 1; i >= 0; i-- {
+			if err := locks[i](); err != nil && result == nil {
+				result = err
+			}
+		}
+		return result
+	}
+
+	var reader *bufio.Reader
+	seen := make(map[string]bool)
+	for _, name := range names {
+		for current := name; current != "" && !seen[current]; current = profiles.Parent(current) {
+			seen[current] = true
+			if profiles.Locked(current) {
+				if reader == nil {
+					reader = bufio.NewReader(in)
+				}
+				passphrase, err := profilePassphrase(current, reader, in)
+				if err != nil {
+					_ = lock()
+					return func() error { return nil }, err
+				}
+				unlock, err := profiles.Unlock(current, passphrase)
+				if err != nil {
+					_ = lock()
+					return func() error { return nil }, err
+				}
+				locks = append(locks, unlock)
+			}
+			if !chain {
+				break
+			}
+		}
+	}
+	return lock, nil
+}
+
+func init() {
+	rootCmd.AddCommand(encryptCmd)
+	encryptCmd.Flags().StringVar(&encryptKeyFile, "key-file", "", "encrypt with the content of this file instead of a passphrase")
+	rootCmd.AddCommand(decryptCmd)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestEncryptProfile(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+
+	assert.EqualError(t, encryptProfile("test", "", strings.NewReader("one\ntwo\n")), "the passphrases are empty or do not match")
+	assert.False(t, profiles.Encrypted("test"))
+
+	assert.NoError(t, encryptProfile("test", "", strings.NewReader("passphrase\npassphrase\n")))
+	assert.True(t, profiles.Encrypted("test"))
+	assert.EqualError(t, encryptProfile("test", "", nil), "profile test is already encrypted")
+	assert.EqualError(t, encryptProfile("unknown", "", nil), "profile unknown does not exist")
+
+	lock, err := unlockProfiles([]string{"test"}, false, strings.NewReader("wrong\n"))
+	assert.EqualError(t, err, "could not decrypt profile test, the passphrase or key file is wrong")
+	assert.NoError(t, lock())
+
+	lock, err = unlockProfiles([]string{"test"}, false, strings.NewReader("passphrase\n"))
+	assert.NoError(t, err)
+	assert.False(t, profiles.Locked("test"))
+	assert.NoError(t, lock())
+	assert.True(t, profiles.Locked("test"))
+
+	assert.Equal(t, "test (encrypted)", describeProfile("test"))
+}
+
+func TestExecMvnEncryptedProfile(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	_ = profiles.Set("test")
+	_ = profiles.SetMvnOpts("test", "-Xmx2g")
+	keyFile := filepath.Join(t.TempDir(), "test.key")
+	_ = os.WriteFile(keyFile, []byte("key"), 0600)
+	_ = profiles.Encrypt("test", nil, keyFile)
+	t.Setenv("MAVEN_OPTS", "")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	var mvnArgs []string
+	mockProvider := func(command string, args ...string) profiles.ShellCommand {
+		mvnArgs = args
+		return &mockShell
+	}
+
+	tempDir := t.TempDir()
+	mvnDir := filepath.Join(tempDir, "maven", "3.9.6", "bin")
+	_ = os.MkdirAll(mvnDir, 0755)
+	_, _ = os.Create(filepath.Join(mvnDir, "mvn"))
+
+	mockShell.On("Output").Return([]byte(tempDir), nil)
+	mockShell.On("Env", mock.MatchedBy(func(env []string) bool {
+		return slices.Contains(env, "MAVEN_OPTS=-Xmx2g")
+	})).Return()
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(nil)
+
+	assert.Equal(t, 0, execMvn([]string{"verify"}, mockProvider))
+	assert.Equal(t, "--settings", mvnArgs[0])
+	assert.NotEqual(t, profiles.File("test"), mvnArgs[1])
+	assert.NoFileExists(t, mvnArgs[1])
+	assert.Empty(t, os.Getenv("MAVEN_OPTS"))
+	assert.True(t, profiles.Locked("test"))
+}
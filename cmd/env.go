@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+)
+
+var envProfile string
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env",
+	Short: "Manage the environment variables of a profile",
+	Long: `With this command you can manage the environment variables that are applied to maven for a profile.
+
+The commands use the active profile, unless a profile is provided with --profile. Variables of a base profile are
+applied first, so a profile can override the variables of the profile it extends.`,
+}
+
+var envSetCmd = &cobra.Command{
+	Use:   "set [key] [value]",
+	Args:  cobra.ExactArgs(2),
+	Short: "Set an environment variable for a profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(envProfile)
+		if profile == "" {
+			return
+		}
+
+		err := profiles.SetEnv(profile, args[0], args[1])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Set %v for profile %v\n", args[0], profile)
+	},
+}
+
+var envUnsetCmd = &cobra.Command{
+	Use:   "unset [key]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Remove an environment variable from a profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(envProfile)
+		if profile == "" {
+			return
+		}
+
+		err := profiles.UnsetEnv(profile, args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Removed %v from profile %v\n", args[0], profile)
+	},
+}
+
+var envLsCmd = &cobra.Command{
+	Use:   "ls",
+	Args:  cobra.NoArgs,
+	Short: "List the environment variables of a profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(envProfile)
+		if profile == "" {
+			return
+		}
+
+		err := printEnv(profile)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func printEnv(profile string) error {
+	chain, err := profiles.Chain(profile)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	found := false
+	for _, p := range chain {
+		vars, err := profiles.Env(p)
+		if err != nil {
+			return err
+		}
+		for _, v := range vars {
+			if seen[v.Key] {
+				continue
+			}
+			seen[v.Key] = true
+
+			if !found {
+				fmt.Printf("Environment of profile %v:\n", profile)
+				found = true
+			}
+			if p == profile {
+				fmt.Printf("  %v=%v\n", v.Key, v.Value)
+			} else {
+				fmt.Printf("  %v=%v (from %v)\n", v.Key, v.Value, p)
+			}
+		}
+	}
+
+	if !found {
+		fmt.Printf("No environment variables set for profile %v\n", profile)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.AddCommand(envSetCmd)
+	envCmd.AddCommand(envUnsetCmd)
+	envCmd.AddCommand(envLsCmd)
+	envCmd.PersistentFlags().StringVarP(&envProfile, "profile", "p", "", "profile to use instead of the active profile")
+	_ = envCmd.RegisterFlagCompletionFunc("profile", profiles.CustomProfileCompletion)
+}
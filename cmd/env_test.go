@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"menv/profiles"
+	"os"
+	"testing"
+)
+
+func TestPrintEnv(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("base")
+	_ = profiles.Create("child")
+	_ = profiles.Create("empty")
+	_ = profiles.Extend("child", "base")
+	_ = profiles.SetEnv("base", "JAVA_HOME", "/jdk/17")
+	_ = profiles.SetEnv("base", "TOKEN", "base")
+	_ = profiles.SetEnv("child", "TOKEN", "child")
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_ = printEnv("child")
+	_ = printEnv("empty")
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, output, "  TOKEN=child\n")
+	assert.Contains(t, output, "  JAVA_HOME=/jdk/17 (from base)\n")
+	assert.NotContains(t, output, "TOKEN=base")
+	assert.Contains(t, output, "No environment variables set for profile empty")
+}
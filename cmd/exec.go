@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io/fs"
+	"menv/profiles"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var execProfile string
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec [--profile profile] -- command [args...]",
+	Args:  cobra.MinimumNArgs(1),
+	Short: "Run a command with the active profile applied",
+	Long: `This command runs any command with the active profile, or the profile provided with --profile, applied to its
+environment, for tools that run maven themselves, like release scripts or test harnesses. The command exits with the
+exit status of the command it runs.
+
+The environment of the command contains:
+  MENV_PROFILE   the profile
+  MENV_SETTINGS  the settings.xml of the profile
+  MAVEN_ARGS     --settings and the other arguments menv passes to maven, followed by the existing MAVEN_ARGS
+  MAVEN_OPTS     the MAVEN_OPTS of the profile
+and the JDK and environment variables of the profile, which take precedence over the MAVEN_OPTS of the profile like
+they do for 'menv mvn'. Maven splits MAVEN_ARGS at whitespace, so a profile whose files are in a directory with
+whitespace in its path cannot be used. Merged settings and resolved secrets are written to temporary
+files, which are removed when the command exits.
+
+Example:
+menv exec --profile work -- ./release.sh`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := execProfile
+		if profile == "" {
+			profile = activeProfile()
+		}
+
+		if code := execInProfile(profile, args, profiles.ExecCmdProvider); code != 0 {
+			os.Exit(code)
+		}
+	},
+}
+
+// execInProfile runs the given command with the given profile applied to its environment and returns its exit
+// status.
+func execInProfile(profile string, args []string, shell func(string, ...string) profiles.ShellCommand) int {
+	if profile != "" && !profiles.Exists(profile) {
+		fmt.Printf("profile %v does not exist\n", profile)
+		return 1
+	}
+
+	lock, err := unlockProfiles([]string{profile}, true, os.Stdin)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer func() {
+		if err := lock(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	mavenArgs, _, cleanup, err := profileMavenArgs(profile, shell)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer cleanup()
+
+	environ, _, err := profileEnviron(profile)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	env := environMap(environ)
+	if profile != "" {
+		env["MENV_PROFILE"] = profile
+		// profileMavenArgs starts with --settings and the settings file
+		env["MENV_SETTINGS"] = mavenArgs[1]
+		// maven splits MAVEN_ARGS at whitespace without removing quotes, so arguments with whitespace cannot be passed
+		for _, arg := range mavenArgs {
+			if strings.ContainsAny(arg, " \t\n") {
+				fmt.Printf("cannot pass %v in MAVEN_ARGS, because it contains whitespace\n", arg)
+				return 1
+			}
+		}
+		if existing := env["MAVEN_ARGS"]; existing != "" {
+			mavenArgs = append(mavenArgs, existing)
+		}
+		env["MAVEN_ARGS"] = strings.Join(mavenArgs, " ")
+	}
+
+	cmd := shell(args[0], args[1:]...)
+
+	cmd.Env(environList(env))
+	cmd.Stdin(os.Stdin)
+	cmd.Stdout(os.Stdout)
+	cmd.Stderr(os.Stderr)
+	err = cmd.Run()
+
+	// like a shell, a command that cannot be run exits with 127, the error is printed by Run
+	var execErr *exec.Error
+	if errors.As(err, &execErr) || errors.Is(err, fs.ErrNotExist) {
+		return 127
+	}
+	return profiles.ExitCode(err)
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().SetInterspersed(false)
+	execCmd.Flags().StringVarP(&execProfile, "profile", "p", "", "profile to use instead of the active profile")
+	_ = execCmd.RegisterFlagCompletionFunc("profile", profiles.CustomProfileCompletion)
+}
@@ -0,0 +1,18 @@
+//go:build linux
+
+package cmd
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// execReplace replaces the menv process with the given command, so no menv process lingers while maven runs. It only
+// returns if the process could not be replaced.
+func execReplace(command string, args []string, env []string) error {
+	path, err := exec.LookPath(command)
+	if err != nil {
+		return err
+	}
+	return syscall.Exec(path, append([]string{command}, args...), env)
+}
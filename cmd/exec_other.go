@@ -0,0 +1,10 @@
+//go:build !linux
+
+package cmd
+
+import "errors"
+
+// execReplace is only supported on Linux, so maven is executed as a child process instead.
+func execReplace(_ string, _ []string, _ []string) error {
+	return errors.New("replacing the menv process is only supported on Linux")
+}
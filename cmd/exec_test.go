@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"menv/config"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecInProfile(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("work")
+	_ = profiles.SetMvnOpts("work", "-Xmx2g")
+	_ = profiles.SetEnv("work", "NEXUS_URL", "https://nexus.example.com")
+	t.Setenv("MAVEN_ARGS", "-B")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	var command []string
+	mockProvider := func(name string, args ...string) profiles.ShellCommand {
+		command = append([]string{name}, args...)
+		return &mockShell
+	}
+
+	var env map[string]string
+	mockShell.On("Env", mock.Anything).Run(func(args mock.Arguments) {
+		env = environMap(args.Get(0).([]string))
+	}).Return()
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(exitError{code: 4})
+
+	assert.Equal(t, 4, execInProfile("work", []string{"./release.sh", "--dry-run"}, mockProvider))
+	assert.Equal(t, []string{"./release.sh", "--dry-run"}, command)
+	assert.Equal(t, "work", env["MENV_PROFILE"])
+	assert.Equal(t, profiles.File("work"), env["MENV_SETTINGS"])
+	assert.Equal(t, "--settings "+profiles.File("work")+" --global-settings "+profiles.File("work")+" -B", env["MAVEN_ARGS"])
+	assert.Equal(t, "-Xmx2g", env["MAVEN_OPTS"])
+	assert.Equal(t, "https://nexus.example.com", env["NEXUS_URL"])
+	mockShell.AssertExpectations(t)
+}
+
+func TestExecInProfileEnvOverridesOpts(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("work")
+	_ = profiles.SetMvnOpts("work", "-Xmx2g")
+	_ = profiles.SetEnv("work", "MAVEN_OPTS", "-Xmx4g")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) profiles.ShellCommand {
+		return &mockShell
+	}
+
+	var env map[string]string
+	mockShell.On("Env", mock.Anything).Run(func(args mock.Arguments) {
+		env = environMap(args.Get(0).([]string))
+	}).Return()
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(nil)
+
+	assert.Equal(t, 0, execInProfile("work", []string{"true"}, mockProvider))
+	assert.Equal(t, "-Xmx4g", env["MAVEN_OPTS"])
+}
+
+func TestExecInProfileWhitespace(t *testing.T) {
+	initMvnTest(t)
+	root := filepath.Join(t.TempDir(), "with spaces")
+	_ = os.MkdirAll(root, 0755)
+	testConfig := config.Config{MenvRoot: root}
+	config.Set(testConfig)
+	profiles.Init(testConfig)
+	_ = profiles.Create("work")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) profiles.ShellCommand {
+		return &mockShell
+	}
+
+	assert.Equal(t, 1, execInProfile("work", []string{"true"}, mockProvider))
+	mockShell.AssertExpectations(t)
+}
+
+func TestExecInProfileUnknown(t *testing.T) {
+	initMvnTest(t)
+
+	assert.Equal(t, 1, execInProfile("unknown", []string{"true"}, profiles.ExecCmdProvider))
+}
+
+func TestExecInProfileCommandNotFound(t *testing.T) {
+	initMvnTest(t)
+
+	assert.Equal(t, 127, execInProfile("", []string{filepath.Join(t.TempDir(), "missing")}, profiles.ExecCmdProvider))
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+	"os"
+)
+
+var (
+	exportOutput string
+	exportRedact bool
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:               "export [profile...]",
+	Args:              cobra.MinimumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Exports profiles as a bundle to share them",
+	Long: `This command packages the settings.xml, MAVEN_OPTS and all other files of the provided profiles in a .tar.gz
+bundle with a manifest. Profiles extended by the provided profiles are included as well.
+
+With --redact the passwords and passphrases of the settings.xml files and the values of the environment variables are
+replaced by placeholders, which are asked for when the bundle is imported with 'menv import-bundle'. The master
+passwords of the profiles are left out.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := exportBundle(args, exportOutput, exportRedact)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func exportBundle(names []string, output string, redact bool) error {
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := profiles.Export(file, names, redact)
+	closeErr := file.Close()
+	if err != nil {
+		_ = os.Remove(output)
+		return err
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	for _, p := range manifest.Profiles {
+		fmt.Printf("Exported profile %v (%v files", p.Name, len(p.Files))
+		if len(p.Secrets) > 0 {
+			fmt.Printf(", %v secrets redacted", len(p.Secrets))
+		}
+		fmt.Println(")")
+	}
+	fmt.Printf("Written bundle to %v\n", output)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringVarP(&exportOutput, "output", "o", "menv-profiles.tar.gz", "file to write the bundle to")
+	exportCmd.Flags().BoolVar(&exportRedact, "redact", false, "replace passwords, passphrases and environment variables with placeholders")
+}
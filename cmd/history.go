@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+	"strings"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:               "history [profile]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Show the history of the provided profile, or the active profile if none is provided",
+	Long: `Before the settings.xml or MAVEN_OPTS of a profile are changed by 'menv edit', 'menv editopts', an import or a
+rollback, the previous version is recorded in the history of the profile. This command lists these snapshots with the
+changes made after them. Use 'menv rollback' to restore a snapshot.
+
+The number of snapshots kept per profile is configured with history_retention, or MENV_HISTORY_RETENTION.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(firstArg(args))
+		if profile == "" {
+			return
+		}
+
+		err := printHistory(profile)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func printHistory(profile string) error {
+	history, err := profiles.History(profile)
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		fmt.Printf("No history for profile %v\n", profile)
+		return nil
+	}
+
+	fmt.Printf("History of profile %v:\n", profile)
+	for i := len(history) - 1; i >= 0; i-- {
+		snapshot := history[i]
+		changes, err := profiles.Changes(profile, snapshot.Rev)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %4d  %v  %-9v %v\n", snapshot.Rev, snapshot.Created.Local().Format("2006-01-02 15:04:05"), snapshot.Reason, describeChanges(changes))
+	}
+	return nil
+}
+
+func describeChanges(changes []profiles.FileChange) string {
+	if len(changes) == 0 {
+		return "no changes"
+	}
+
+	descriptions := make([]string, 0, len(changes))
+	for _, change := range changes {
+		descriptions = append(descriptions, fmt.Sprintf("%v +%v -%v", change.Name, change.Added, change.Removed))
+	}
+	return strings.Join(descriptions, ", ")
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
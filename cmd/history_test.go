@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"menv/profiles"
+	"os"
+	"testing"
+)
+
+func TestPrintHistory(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	_ = profiles.Create("empty")
+	_ = profiles.Import("test", []byte("<settings/>"), "-Xmx2g", true)
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_ = printHistory("test")
+	_ = printHistory("empty")
+	err := printHistory("unknown")
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, output, "History of profile test:\n")
+	assert.Regexp(t, `\n     1  \d{4}-\d\d-\d\d \d\d:\d\d:\d\d  import    settings.xml \+1 -\d+, maven_opts \+1 -0\n`, output)
+	assert.Contains(t, output, "No history for profile empty\n")
+	assert.EqualError(t, err, "profile unknown does not exist")
+}
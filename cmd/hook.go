@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+	"menv/profiles"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	// hookStateVar holds the profile and the .menv_profile the shell hook applied last.
+	hookStateVar = "MENV_HOOK_STATE"
+	// hookRestoreVar holds the values the variables changed by the shell hook had before, to restore them.
+	hookRestoreVar = "MENV_HOOK_RESTORE"
+)
+
+// hookCmd represents the hook command
+var hookCmd = &cobra.Command{
+	Use:       "hook [bash|zsh|fish]",
+	Hidden:    true,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Short:     "Print the commands that apply the active profile to the shell, used by the hook of 'menv init'",
+	Run: func(cmd *cobra.Command, args []string) {
+		if _, ok := shellHooks[args[0]]; !ok {
+			fmt.Fprintf(os.Stderr, "menv: unsupported shell %v\n", args[0])
+			return
+		}
+
+		changes, err := hookChanges(os.Environ())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "menv: %v\n", err)
+			return
+		}
+		fmt.Print(formatExports(args[0], changes))
+	},
+}
+
+// hookChanges returns the variables to change in a shell with the given environment to apply the active profile, with
+// nil for variables to unset. Nothing is changed when the shell already uses the active profile and .menv_profile.
+// Variables changed for a previous profile are restored first.
+func hookChanges(environ []string) (map[string]*string, error) {
+	current := environMap(environ)
+
+	profile, path := profiles.Active()
+	state := ""
+	if profiles.Exists(profile) {
+		state = profile + ":" + path
+	} else {
+		profile = ""
+	}
+	if state == current[hookStateVar] {
+		return map[string]*string{}, nil
+	}
+
+	restore, err := decodeRestore(current[hookRestoreVar])
+	if err != nil {
+		return nil, err
+	}
+	base := environMap(environ)
+	delete(base, hookStateVar)
+	delete(base, hookRestoreVar)
+	for key, value := range restore {
+		if value == nil {
+			delete(base, key)
+		} else {
+			base[key] = *value
+		}
+	}
+
+	target := environMap(environList(base))
+	if profile != "" {
+		target["MENV_PROFILE"] = profile
+		if profiles.MvnOptsExists(profile) {
+			if opts := profiles.MvnOpts(profile); opts != "" {
+				target["MAVEN_OPTS"] = opts
+			} else {
+				delete(target, "MAVEN_OPTS")
+			}
+		}
+		env, err := profiles.Environ(profile, environList(target))
+		if err != nil {
+			return nil, err
+		}
+		target = environMap(env)
+	}
+
+	// the values of the variables changed for the profile before the change, to restore them later
+	restored := make(map[string]*string)
+	for key := range target {
+		if value, ok := base[key]; !ok {
+			restored[key] = nil
+		} else if value != target[key] {
+			restored[key] = &value
+		}
+	}
+	for key := range base {
+		if _, ok := target[key]; !ok {
+			value := base[key]
+			restored[key] = &value
+		}
+	}
+
+	changes := make(map[string]*string)
+	for _, key := range append(mapKeys(restore), mapKeys(restored)...) {
+		value, inTarget := target[key]
+		old, inCurrent := current[key]
+		switch {
+		case !inTarget && inCurrent:
+			changes[key] = nil
+		case inTarget && (!inCurrent || old != value):
+			changes[key] = &value
+		}
+	}
+
+	if state == "" {
+		changes[hookStateVar] = nil
+		changes[hookRestoreVar] = nil
+		return changes, nil
+	}
+	encoded, err := encodeRestore(restored)
+	if err != nil {
+		return nil, err
+	}
+	changes[hookStateVar] = &state
+	changes[hookRestoreVar] = &encoded
+	return changes, nil
+}
+
+// formatExports returns the commands of the given shell that apply the given changes, in alphabetical order.
+func formatExports(shell string, changes map[string]*string) string {
+	var builder strings.Builder
+	for _, key := range mapKeys(changes) {
+		value := changes[key]
+		switch {
+		case value == nil && shell == "fish":
+			builder.WriteString(fmt.Sprintf("set -e %v;\n", key))
+		case value == nil:
+			builder.WriteString(fmt.Sprintf("unset %v;\n", key))
+		case shell == "fish":
+			builder.WriteString(fmt.Sprintf("set -gx %v %v;\n", key, shellQuote(shell, *value)))
+		default:
+			builder.WriteString(fmt.Sprintf("export %v=%v;\n", key, shellQuote(shell, *value)))
+		}
+	}
+	return builder.String()
+}
+
+func encodeRestore(restore map[string]*string) (string, error) {
+	data, err := yaml.Marshal(restore)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+func decodeRestore(value string) (map[string]*string, error) {
+	restore := make(map[string]*string)
+	if value == "" {
+		return restore, nil
+	}
+
+	data, err := base64.StdEncoding.DecodeString(value)
+	if err == nil {
+		err = yaml.Unmarshal(data, &restore)
+	}
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("invalid %v: %v", hookRestoreVar, err))
+	}
+	return restore, nil
+}
+
+func environMap(environ []string) map[string]string {
+	result := make(map[string]string)
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		result[key] = value
+	}
+	return result
+}
+
+// environList returns the given variables as an environment, sorted by name.
+func environList(values map[string]string) []string {
+	result := make([]string, 0, len(values))
+	for _, key := range mapKeys(values) {
+		result = append(result, key+"="+values[key])
+	}
+	return result
+}
+
+func mapKeys[V any](values map[string]V) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// applyChanges applies the changes of the shell hook to the given environment, like the shell would.
+func applyChanges(environ []string, changes map[string]*string) []string {
+	values := environMap(environ)
+	for key, value := range changes {
+		if value == nil {
+			delete(values, key)
+		} else {
+			values[key] = *value
+		}
+	}
+	return environList(values)
+}
+
+func TestHookChanges(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("work")
+	_ = profiles.SetMvnOpts("work", "-Xmx2g")
+	_ = profiles.SetEnv("work", "NEXUS_URL", "https://nexus.example.com")
+
+	project := t.TempDir()
+	_ = os.Chdir(project)
+	_ = profiles.Set("work")
+	_ = os.MkdirAll(filepath.Join(project, "module"), 0755)
+
+	environ := []string{"PATH=/usr/bin", "MAVEN_OPTS=-Xmx1g"}
+	changes, err := hookChanges(environ)
+	assert.NoError(t, err)
+	environ = applyChanges(environ, changes)
+	values := environMap(environ)
+	assert.Equal(t, "work", values["MENV_PROFILE"])
+	assert.Equal(t, "-Xmx2g", values["MAVEN_OPTS"])
+	assert.Equal(t, "https://nexus.example.com", values["NEXUS_URL"])
+	assert.Equal(t, "/usr/bin", values["PATH"])
+
+	// nothing is exported again within the same project
+	_ = os.Chdir(filepath.Join(project, "module"))
+	changes, err = hookChanges(environ)
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	// leaving the project restores the previous environment
+	_ = os.Chdir(t.TempDir())
+	changes, err = hookChanges(environ)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"MAVEN_OPTS=-Xmx1g", "PATH=/usr/bin"}, applyChanges(environ, changes))
+}
+
+func TestFormatExports(t *testing.T) {
+	value := "it's a \\ value"
+	changes := map[string]*string{"MAVEN_OPTS": &value, "OLD": nil}
+
+	assert.Equal(t, "export MAVEN_OPTS='it'\"'\"'s a \\ value';\nunset OLD;\n", formatExports("bash", changes))
+	assert.Equal(t, "set -gx MAVEN_OPTS 'it\\'s a \\\\ value';\nset -e OLD;\n", formatExports("fish", changes))
+}
+
+func TestShellHook(t *testing.T) {
+	script, err := shellHook("zsh")
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(script, " hook zsh)"))
+	assert.Contains(t, script, "chpwd_functions=(_menv_hook $chpwd_functions)")
+
+	_, err = shellHook("tcsh")
+	assert.EqualError(t, err, "unsupported shell tcsh, use bash, zsh or fish")
+}
@@ -0,0 +1,146 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	importForce    bool
+	importOptsFrom string
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [profile] [path]",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Create a profile from an existing settings.xml",
+	Long: `With this command you can create a profile from an existing settings.xml. When no path is provided,
+~/.m2/settings.xml is imported.
+
+The settings.xml is validated before it is imported. An existing profile is only overwritten when --force is provided,
+all its files, like its MAVEN_OPTS and environment variables, are replaced.
+
+With --opts-from the MAVEN_OPTS of the profile are imported as well, from one of the following sources:
+  env         the MAVEN_OPTS environment variable
+  mavenrc     the MAVEN_OPTS set in ~/.mavenrc
+  jvm.config  the .mvn/jvm.config of the current maven project`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := args[0]
+		path := defaultSettingsPath()
+		if len(args) > 1 {
+			path = args[1]
+		}
+
+		err := importProfile(profile, path, importOptsFrom, importForce)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		fmt.Printf("Imported %v as profile %v\n", path, profile)
+	},
+}
+
+func defaultSettingsPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".m2", "settings.xml")
+}
+
+func importProfile(profile string, path string, optsFrom string, force bool) error {
+	opts := ""
+	if optsFrom != "" {
+		var err error
+		opts, err = importedOpts(optsFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return errors.New(fmt.Sprintf("could not read %v: %v", path, err))
+	}
+
+	return profiles.Import(profile, data, opts, force)
+}
+
+// importedOpts returns the MAVEN_OPTS from the given source.
+func importedOpts(source string) (string, error) {
+	switch source {
+	case "env":
+		opts, b := os.LookupEnv("MAVEN_OPTS")
+		if !b {
+			return "", errors.New("MAVEN_OPTS is not set")
+		}
+		return opts, nil
+	case "mavenrc":
+		home, _ := os.UserHomeDir()
+		return mavenrcOpts(filepath.Join(home, ".mavenrc"))
+	case "jvm.config":
+		return jvmConfigOpts(filepath.Join(projectRoot(), ".mvn", "jvm.config"))
+	default:
+		return "", errors.New(fmt.Sprintf("unknown MAVEN_OPTS source %v, use env, mavenrc or jvm.config", source))
+	}
+}
+
+// mavenrcOpts returns the MAVEN_OPTS set in the given .mavenrc. A reference to MAVEN_OPTS itself, used to append to
+// the MAVEN_OPTS of the environment, is left out.
+func mavenrcOpts(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("could not read %v: %v", path, err))
+	}
+
+	opts, found := "", false
+	for _, line := range strings.Split(string(data), "\n") {
+		vars, err := profiles.ParseEnv(line)
+		if err != nil || len(vars) == 0 || vars[0].Key != "MAVEN_OPTS" {
+			continue
+		}
+
+		value := vars[0].Value
+		if !vars[0].Literal {
+			value = os.Expand(value, func(name string) string {
+				if name == "MAVEN_OPTS" {
+					return opts
+				}
+				return os.Getenv(name)
+			})
+		}
+		opts, found = strings.Join(strings.Fields(value), " "), true
+	}
+
+	if !found {
+		return "", errors.New(fmt.Sprintf("%v does not set MAVEN_OPTS", path))
+	}
+	return opts, nil
+}
+
+// jvmConfigOpts returns the options of the given .mvn/jvm.config on a single line.
+func jvmConfigOpts(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("could not read %v: %v", path, err))
+	}
+
+	opts := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		opts = append(opts, line)
+	}
+	return strings.Join(opts, " "), nil
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.Flags().BoolVarP(&importForce, "force", "f", false, "overwrite an existing profile")
+	importCmd.Flags().StringVar(&importOptsFrom, "opts-from", "", "import MAVEN_OPTS from env, mavenrc or jvm.config")
+}
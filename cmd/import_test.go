@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestImportProfile(t *testing.T) {
+	initMvnTest(t)
+	settings := filepath.Join(t.TempDir(), "settings.xml")
+	_ = os.WriteFile(settings, []byte("<settings/>"), 0644)
+	t.Setenv("MAVEN_OPTS", "-Xmx1g")
+
+	assert.NoError(t, importProfile("plain", settings, "", false))
+	assert.True(t, profiles.Exists("plain"))
+	assert.False(t, profiles.MvnOptsExists("plain"))
+
+	assert.NoError(t, importProfile("withopts", settings, "env", false))
+	assert.Equal(t, "-Xmx1g", profiles.MvnOpts("withopts"))
+
+	assert.EqualError(t, importProfile("plain", settings, "", false), "profile plain already exists, use --force to overwrite it")
+	assert.EqualError(t, importProfile("other", settings, "unknown", false), "unknown MAVEN_OPTS source unknown, use env, mavenrc or jvm.config")
+	assert.ErrorContains(t, importProfile("other", filepath.Join(t.TempDir(), "missing.xml"), "", false), "could not read")
+	assert.False(t, profiles.Exists("other"))
+}
+
+func TestMavenrcOpts(t *testing.T) {
+	mavenrc := filepath.Join(t.TempDir(), ".mavenrc")
+	_ = os.WriteFile(mavenrc, []byte("#!/bin/sh\nJAVA_HOME=/jdk\nexport MAVEN_OPTS=\"-Xmx2g\"\nMAVEN_OPTS=\"$MAVEN_OPTS -Dfoo=bar\"\nif [ -f x ]; then\n  echo x\nfi\n"), 0644)
+
+	opts, err := mavenrcOpts(mavenrc)
+	assert.NoError(t, err)
+	assert.Equal(t, "-Xmx2g -Dfoo=bar", opts)
+
+	_ = os.WriteFile(mavenrc, []byte("JAVA_HOME=/jdk\n"), 0644)
+	_, err = mavenrcOpts(mavenrc)
+	assert.EqualError(t, err, mavenrc+" does not set MAVEN_OPTS")
+}
+
+func TestJvmConfigOpts(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "jvm.config")
+	_ = os.WriteFile(config, []byte("# memory\n-Xmx2g\n\n-XX:+UseG1GC -Dfile.encoding=UTF-8\n"), 0644)
+
+	opts, err := jvmConfigOpts(config)
+	assert.NoError(t, err)
+	assert.Equal(t, "-Xmx2g -XX:+UseG1GC -Dfile.encoding=UTF-8", opts)
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"menv/profiles"
+	"os"
+)
+
+var importBundleForce bool
+
+// importBundleCmd represents the import-bundle command
+var importBundleCmd = &cobra.Command{
+	Use:   "import-bundle [bundle]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Imports the profiles of a bundle created with 'menv export'",
+	Long: `This command restores the profiles of a bundle created with 'menv export'. Existing profiles are only
+overwritten when --force is provided.
+
+When the bundle was exported with --redact, the redacted passwords, passphrases and environment variables are asked
+for.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		err := importBundle(args[0], importBundleForce, os.Stdin)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+	},
+}
+
+func importBundle(path string, force bool, in io.Reader) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(in)
+	manifest, err := profiles.ImportBundle(file, force, func(profile string, secret profiles.Secret) (string, error) {
+		return promptSecret(reader, in, fmt.Sprintf("Enter the %v for profile %v: ", secret.Description(), profile))
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, p := range manifest.Profiles {
+		fmt.Printf("Imported profile %v\n", p.Name)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(importBundleCmd)
+	importBundleCmd.Flags().BoolVarP(&importBundleForce, "force", "f", false, "overwrite existing profiles")
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExportImportBundle(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("team")
+	_ = os.WriteFile(profiles.File("team"), []byte("<settings><servers><server><id>nexus</id><password>s3cret</password></server></servers></settings>"), 0644)
+	bundle := filepath.Join(t.TempDir(), "team.tar.gz")
+
+	assert.NoError(t, exportBundle([]string{"team"}, bundle, true))
+	assert.ErrorContains(t, exportBundle([]string{"unknown"}, filepath.Join(t.TempDir(), "x.tar.gz"), true), "profile unknown does not exist")
+
+	initMvnTest(t)
+	assert.EqualError(t, importBundle(bundle, false, strings.NewReader("")), "no value entered")
+	assert.False(t, profiles.Exists("team"))
+
+	assert.NoError(t, importBundle(bundle, false, strings.NewReader("typed secret\n")))
+	data, _ := os.ReadFile(profiles.File("team"))
+	assert.Contains(t, string(data), "<password>typed secret</password>")
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+const bashHook = `_menv_hook() {
+  local previous_exit_status=$?
+  eval "$(%[1]v hook bash)"
+  return $previous_exit_status
+}
+if [[ ";${PROMPT_COMMAND[*]:-};" != *";_menv_hook;"* ]]; then
+  PROMPT_COMMAND="_menv_hook${PROMPT_COMMAND:+;$PROMPT_COMMAND}"
+fi
+`
+
+const zshHook = `_menv_hook() {
+  eval "$(%[1]v hook zsh)"
+}
+typeset -ag precmd_functions chpwd_functions
+if (( ! ${precmd_functions[(I)_menv_hook]} )); then
+  precmd_functions=(_menv_hook $precmd_functions)
+fi
+if (( ! ${chpwd_functions[(I)_menv_hook]} )); then
+  chpwd_functions=(_menv_hook $chpwd_functions)
+fi
+`
+
+const fishHook = `function __menv_hook --on-event fish_prompt --on-variable PWD
+    %[1]v hook fish | source
+end
+`
+
+// shellHooks are the scripts that install the hook, by shell. The placeholder is replaced by the quoted path of menv.
+var shellHooks = map[string]string{
+	"bash": bashHook,
+	"zsh":  zshHook,
+	"fish": fishHook,
+}
+
+// initCmd represents the init command
+var initCmd = &cobra.Command{
+	Use:       "init [bash|zsh|fish]",
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	Short:     "Print the shell hook that applies the active profile to the shell",
+	Long: `This command prints a hook for your shell, which applies the active profile whenever the directory changes: it
+sets MENV_PROFILE, and exports the MAVEN_OPTS and the environment variables of the profile. Like direnv, the variables
+are only exported again when the resolved .menv_profile changes, and the previous values are restored when you leave
+the directory of a profile.
+
+Add the hook to the configuration of your shell:
+
+bash (~/.bashrc):               eval "$(menv init bash)"
+zsh (~/.zshrc):                 eval "$(menv init zsh)"
+fish (~/.config/fish/config.fish): menv init fish | source`,
+	Run: func(cmd *cobra.Command, args []string) {
+		script, err := shellHook(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Print(script)
+	},
+}
+
+func shellHook(shell string) (string, error) {
+	hook, ok := shellHooks[shell]
+	if !ok {
+		return "", errors.New(fmt.Sprintf("unsupported shell %v, use bash, zsh or fish", shell))
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		self = "menv"
+	}
+	return fmt.Sprintf(hook, shellQuote(shell, self)), nil
+}
+
+// shellQuote quotes the given value as a single quoted string of the given shell.
+func shellQuote(shell string, value string) string {
+	if shell == "fish" {
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+)
+
+var javaProfile string
+
+// javaCmd represents the java command
+var javaCmd = &cobra.Command{
+	Use:   "java",
+	Short: "Manage the JDK used by maven for a profile",
+	Long: `With this command you can list the JDKs installed on this machine and bind a JDK to a profile.
+
+JDKs are discovered in JAVA_HOME, SDKMAN (~/.sdkman/candidates/java), ~/.jdks, /usr/lib/jvm and
+/Library/Java/JavaVirtualMachines. When a profile has a JDK, maven is executed with JAVA_HOME set to the JDK and its
+bin folder prepended to the PATH.`,
+}
+
+var javaLsCmd = &cobra.Command{
+	Use:   "ls",
+	Args:  cobra.NoArgs,
+	Short: "List the discovered JDKs",
+	Run: func(cmd *cobra.Command, args []string) {
+		printJdks(discoverJdks(), selectedJdk(activeProfile()))
+	},
+}
+
+var javaUseCmd = &cobra.Command{
+	Use:   "use [version|path]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Bind a JDK to the active profile, or the profile provided with --profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(javaProfile)
+		if profile == "" {
+			return
+		}
+
+		err := useJava(profile, args[0])
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var javaClearCmd = &cobra.Command{
+	Use:   "clear",
+	Args:  cobra.NoArgs,
+	Short: "Remove the JDK bound to the active profile, or the profile provided with --profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(javaProfile)
+		if profile == "" {
+			return
+		}
+
+		err := profiles.ClearJava(profile)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func printJdks(jdks []jdk, selected jdk) {
+	if len(jdks) == 0 {
+		fmt.Println("No JDKs found")
+		return
+	}
+
+	fmt.Println("Available JDKs:")
+	for _, j := range jdks {
+		if j.Home == selected.Home {
+			fmt.Print("* ")
+		} else {
+			fmt.Print("  ")
+		}
+		fmt.Printf("%-12v %v (%v)\n", j.Version, j.Home, j.Source)
+	}
+}
+
+// selectedJdk returns the JDK bound to the given profile, if it is installed.
+func selectedJdk(profile string) jdk {
+	if profile == "" {
+		return jdk{}
+	}
+	java := profiles.Java(profile)
+	if java == "" {
+		return jdk{}
+	}
+	selected, _ := resolveJdk(java)
+	return selected
+}
+
+func useJava(profile string, java string) error {
+	selected, err := resolveJdk(java)
+	if err != nil {
+		return err
+	}
+
+	err = profiles.SetJava(profile, java)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Profile %v uses JDK %v (%v)\n", profile, selected.Version, selected.Home)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(javaCmd)
+	javaCmd.AddCommand(javaLsCmd)
+	javaCmd.AddCommand(javaUseCmd)
+	javaCmd.AddCommand(javaClearCmd)
+	javaCmd.PersistentFlags().StringVarP(&javaProfile, "profile", "p", "", "profile to use instead of the active profile")
+	_ = javaCmd.RegisterFlagCompletionFunc("profile", profiles.CustomProfileCompletion)
+}
@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// jdk is a JDK installation found on this machine.
+type jdk struct {
+	Version string
+	Vendor  string
+	Home    string
+	Source  string
+}
+
+// jdkLocation is a glob pattern matching JDK homes, with the source reported by menv java ls.
+type jdkLocation struct {
+	source  string
+	pattern string
+}
+
+// systemJdkLocations are the well known locations of JDK installations on Linux and macOS.
+var systemJdkLocations = []string{"/usr/lib/jvm/*", "/Library/Java/JavaVirtualMachines/*/Contents/Home"}
+
+var majorVersionPattern = regexp.MustCompile(`\d+`)
+
+// discoverJdks returns the JDKs found in JAVA_HOME, SDKMAN, ~/.jdks and the system locations, newest first.
+func discoverJdks() []jdk {
+	home, _ := os.UserHomeDir()
+	locations := []jdkLocation{
+		{"java_home", os.Getenv("JAVA_HOME")},
+		{"sdkman", filepath.Join(sdkmanDir(), "candidates", "java", "*")},
+		{"jdks", filepath.Join(home, ".jdks", "*")},
+	}
+	for _, pattern := range systemJdkLocations {
+		locations = append(locations, jdkLocation{"system", pattern})
+	}
+
+	jdks := make([]jdk, 0)
+	seen := make(map[string]bool)
+	for _, location := range locations {
+		if location.pattern == "" {
+			continue
+		}
+		matches, _ := filepath.Glob(location.pattern)
+		for _, match := range matches {
+			resolved, err := filepath.EvalSymlinks(match)
+			if err != nil || seen[resolved] || !isExecutable(filepath.Join(match, "bin", "java")) {
+				continue
+			}
+			seen[resolved] = true
+			jdks = append(jdks, jdk{Version: jdkVersion(match), Vendor: jdkRelease(match)["IMPLEMENTOR"], Home: match, Source: location.source})
+		}
+	}
+
+	sort.SliceStable(jdks, func(i, j int) bool {
+		return compareVersions(jdks[i].Version, jdks[j].Version) > 0
+	})
+	return jdks
+}
+
+// jdkVersion returns the version of the JDK in the given home, read from its release file or, if that is missing,
+// from the name of the directory.
+func jdkVersion(home string) string {
+	if version := jdkRelease(home)["JAVA_VERSION"]; version != "" {
+		return normalizeJavaVersion(version)
+	}
+
+	if version := versionPattern.FindString(filepath.Base(home)); version != "" {
+		return normalizeJavaVersion(version)
+	}
+	if version := majorVersionPattern.FindString(filepath.Base(home)); version != "" {
+		return version
+	}
+	return "unknown"
+}
+
+// jdkRelease returns the properties of the release file of the JDK in the given home.
+func jdkRelease(home string) map[string]string {
+	properties := make(map[string]string)
+
+	file, err := os.Open(filepath.Join(home, "release"))
+	if err != nil {
+		return properties
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if found {
+			properties[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return properties
+}
+
+// majorJavaVersion returns the major version of a normalized java version, like 17 for 17.0.9.
+func majorJavaVersion(version string) string {
+	major, _, _ := strings.Cut(version, ".")
+	return major
+}
+
+// normalizeJavaVersion converts legacy versions like 1.8.0_392 to 8.0.392, so they can be compared with modern ones.
+func normalizeJavaVersion(version string) string {
+	version = strings.ReplaceAll(version, "_", ".")
+	if strings.HasPrefix(version, "1.") {
+		version = strings.TrimPrefix(version, "1.")
+	}
+	return version
+}
+
+// resolveJdk returns the JDK matching the given version or JDK home.
+func resolveJdk(java string) (jdk, error) {
+	if strings.ContainsRune(java, filepath.Separator) {
+		if !isExecutable(filepath.Join(java, "bin", "java")) {
+			return jdk{}, errors.New(fmt.Sprintf("%v is not a JDK home", java))
+		}
+		return jdk{Version: jdkVersion(java), Vendor: jdkRelease(java)["IMPLEMENTOR"], Home: java, Source: "path"}, nil
+	}
+
+	for _, candidate := range discoverJdks() {
+		if matchesVersion(candidate.Version, normalizeJavaVersion(java)) {
+			return candidate, nil
+		}
+	}
+	return jdk{}, errors.New(fmt.Sprintf("JDK %v is not installed, run 'menv java ls' to list the installed JDKs", java))
+}
+
+// applyJdk sets JAVA_HOME to the given JDK and prepends its bin folder to the PATH of the given environment.
+func applyJdk(environ []string, java jdk) []string {
+	result := make([]string, 0, len(environ)+2)
+	path := ""
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		switch key {
+		case "JAVA_HOME":
+		case "PATH":
+			path = value
+		default:
+			result = append(result, entry)
+		}
+	}
+
+	bin := filepath.Join(java.Home, "bin")
+	if path != "" {
+		bin += string(os.PathListSeparator) + path
+	}
+	return append(result, "JAVA_HOME="+java.Home, "PATH="+bin)
+}
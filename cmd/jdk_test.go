@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createJdk(t *testing.T, home string, version string) string {
+	t.Helper()
+	_ = os.MkdirAll(filepath.Join(home, "bin"), 0755)
+	_ = os.WriteFile(filepath.Join(home, "bin", "java"), []byte("#!/bin/sh\n"), 0755)
+	if version != "" {
+		_ = os.WriteFile(filepath.Join(home, "release"), []byte("IMPLEMENTOR=\"Eclipse Adoptium\"\nJAVA_VERSION=\""+version+"\"\n"), 0644)
+	}
+	return home
+}
+
+func initJdkTest(t *testing.T) string {
+	t.Helper()
+	initMvnTest(t)
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("JAVA_HOME", "")
+	t.Setenv("SDKMAN_DIR", filepath.Join(home, ".sdkman"))
+
+	original := systemJdkLocations
+	systemJdkLocations = []string{filepath.Join(home, "jvm", "*")}
+	t.Cleanup(func() { systemJdkLocations = original })
+	return home
+}
+
+func TestDiscoverJdks(t *testing.T) {
+	home := initJdkTest(t)
+	createJdk(t, filepath.Join(home, "jvm", "java-8-openjdk"), "1.8.0_392")
+	createJdk(t, filepath.Join(home, ".sdkman", "candidates", "java", "21.0.2-tem"), "21.0.2")
+	createJdk(t, filepath.Join(home, ".jdks", "corretto-17.0.9"), "")
+	_ = os.MkdirAll(filepath.Join(home, ".jdks", "broken"), 0755)
+
+	jdks := discoverJdks()
+	assert.Len(t, jdks, 3)
+	assert.Equal(t, jdk{Version: "21.0.2", Vendor: "Eclipse Adoptium", Home: filepath.Join(home, ".sdkman", "candidates", "java", "21.0.2-tem"), Source: "sdkman"}, jdks[0])
+	assert.Equal(t, jdk{Version: "17.0.9", Home: filepath.Join(home, ".jdks", "corretto-17.0.9"), Source: "jdks"}, jdks[1])
+	assert.Equal(t, jdk{Version: "8.0.392", Vendor: "Eclipse Adoptium", Home: filepath.Join(home, "jvm", "java-8-openjdk"), Source: "system"}, jdks[2])
+}
+
+func TestResolveJdk(t *testing.T) {
+	home := initJdkTest(t)
+	java8 := createJdk(t, filepath.Join(home, "jvm", "java-8-openjdk"), "1.8.0_392")
+	java17 := createJdk(t, filepath.Join(home, "jvm", "java-17-openjdk"), "17.0.9")
+
+	selected, err := resolveJdk("17")
+	assert.NoError(t, err)
+	assert.Equal(t, java17, selected.Home)
+
+	selected, err = resolveJdk("1.8")
+	assert.NoError(t, err)
+	assert.Equal(t, java8, selected.Home)
+
+	selected, err = resolveJdk(java8)
+	assert.NoError(t, err)
+	assert.Equal(t, "8.0.392", selected.Version)
+
+	_, err = resolveJdk("11")
+	assert.EqualError(t, err, "JDK 11 is not installed, run 'menv java ls' to list the installed JDKs")
+	_, err = resolveJdk(home)
+	assert.EqualError(t, err, home+" is not a JDK home")
+}
+
+func TestApplyJdk(t *testing.T) {
+	java := jdk{Version: "17.0.9", Home: "/jvm/17"}
+	env := applyJdk([]string{"HOME=/home", "JAVA_HOME=/jvm/8", "PATH=/usr/bin"}, java)
+	assert.Equal(t, []string{"HOME=/home", "JAVA_HOME=/jvm/17", "PATH=/jvm/17/bin:/usr/bin"}, env)
+}
+
+func TestProfileEnvironWithJdk(t *testing.T) {
+	home := initJdkTest(t)
+	java17 := createJdk(t, filepath.Join(home, "jvm", "java-17-openjdk"), "17.0.9")
+	_ = profiles.Create("test")
+
+	_, changed, err := profileEnviron("test")
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	assert.NoError(t, useJava("test", "17"))
+	_ = os.WriteFile(profiles.EnvFile("test"), []byte("JAVA_TOOL=${JAVA_HOME}/bin/jar\n"), 0600)
+
+	env, changed, err := profileEnviron("test")
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, env, "JAVA_HOME="+java17)
+	assert.Contains(t, env, "JAVA_TOOL="+java17+"/bin/jar")
+
+	_ = profiles.SetJava("test", "11")
+	_, _, err = profileEnviron("test")
+	assert.Error(t, err)
+}
+
+func TestPrintActiveProfileWithJdk(t *testing.T) {
+	home := initJdkTest(t)
+	java17 := createJdk(t, filepath.Join(home, "jvm", "java-17-openjdk"), "17.0.9")
+	_ = profiles.Create("test")
+	_ = profiles.SetJava("test", "17")
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printActiveProfile("test", "/path/.menv_profile")
+	printJdks(discoverJdks(), selectedJdk("test"))
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, output, "  JDK 17.0.9 ("+java17+")\n")
+	assert.Contains(t, output, "* 17.0.9       "+java17+" (system)\n")
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"menv/profiles"
+	"strings"
 )
 
 var lsCmd = &cobra.Command{
@@ -30,10 +31,23 @@ func printProfiles(profileList []string) {
 		} else {
 			fmt.Print("  ")
 		}
-		fmt.Println(profile)
+		fmt.Println(describeProfile(profile))
 	}
 }
 
+// describeProfile returns the profile name followed by the chain of profiles it extends, if any, and whether it is
+// encrypted.
+func describeProfile(profile string) string {
+	description := profile
+	if chain, err := profiles.Chain(profile); err == nil && len(chain) > 1 {
+		description = strings.Join(chain, " -> ")
+	}
+	if profiles.Encrypted(profile) {
+		description += " (encrypted)"
+	}
+	return description
+}
+
 func init() {
 	rootCmd.AddCommand(lsCmd)
 }
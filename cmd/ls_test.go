@@ -60,3 +60,29 @@ func TestPrintProfilesOneActive(t *testing.T) {
 	assert.Contains(t, output, "  non_active")
 
 }
+
+func TestDescribeProfile(t *testing.T) {
+	restoreConfig(t)
+	testCfg := config.Config{
+		MenvRoot: t.TempDir(),
+		Editor:   "vi",
+	}
+	config.Set(testCfg)
+	profiles.Init(testCfg)
+	_ = os.Chdir(t.TempDir())
+
+	_ = createProfile("base", "")
+	_ = createProfile("child", "base")
+
+	assert.Equal(t, "base", describeProfile("base"))
+	assert.Equal(t, "child -> base", describeProfile("child"))
+	assert.Equal(t, "unknown", describeProfile("unknown"))
+	assert.EqualError(t, createProfile("other", "unknown"), "profile unknown does not exist")
+	assert.False(t, profiles.Exists("other"))
+
+	_ = createProfile("broken", "")
+	_ = os.WriteFile(profiles.ParentFile("broken"), []byte("missing\n"), 0644)
+	assert.EqualError(t, createProfile("other", "broken"), "profile missing does not exist")
+	assert.False(t, profiles.Exists("other"), "the profile should be removed when extending it fails")
+	assert.NoError(t, createProfile("other", "base"))
+}
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+	"slices"
+	"sort"
+)
+
+var mavenProfile string
+
+// mavenCmd represents the maven command
+var mavenCmd = &cobra.Command{
+	Use:   "maven",
+	Short: "Manage the maven installations used by menv",
+	Long: `With this command you can list the maven installations menv discovered, and pin a maven version for a
+folder and its children or for a profile.
+
+A version pinned for a folder takes precedence over a version pinned for the active profile. When a maven wrapper
+is found, the wrapper is used regardless of the pinned version, unless the wrapper is disabled.`,
+}
+
+var mavenLsCmd = &cobra.Command{
+	Use:   "ls",
+	Args:  cobra.NoArgs,
+	Short: "List the discovered maven installations, newest version first",
+	Run: func(cmd *cobra.Command, args []string) {
+		installations, _ := discoverMavenInstallations(profiles.ExecCmdProvider)
+		version, _ := profiles.PinnedMavenVersion(activeProfile())
+		printMavenInstallations(installations, version)
+	},
+}
+
+var mavenUseCmd = &cobra.Command{
+	Use:   "use [version]",
+	Args:  cobra.ExactArgs(1),
+	Short: "Pin a maven version for this folder and children, or for a profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := useMavenVersion(args[0], mavenProfile)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var mavenClearCmd = &cobra.Command{
+	Use:   "clear",
+	Args:  cobra.NoArgs,
+	Short: "Remove the maven version pinned for this folder, or for a profile",
+	Run: func(cmd *cobra.Command, args []string) {
+		if mavenProfile == "" {
+			profiles.UnpinMavenVersion()
+			return
+		}
+		err := profiles.ClearMavenVersion(mavenProfile)
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+func printMavenInstallations(installations []mavenInstallation, pinned string) {
+	if len(installations) == 0 {
+		fmt.Println("No maven installations found")
+		return
+	}
+
+	// the selected installation is the first match in discovery order, which is used to run maven
+	selected := ""
+	for _, installation := range installations {
+		if pinned == "" || matchesVersion(installation.Version, pinned) {
+			selected = installation.Path
+			break
+		}
+	}
+
+	sorted := slices.Clone(installations)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return compareVersions(sorted[i].Version, sorted[j].Version) > 0
+	})
+
+	fmt.Println("Available maven installations:")
+	for _, installation := range sorted {
+		if installation.Path == selected {
+			fmt.Print("* ")
+		} else {
+			fmt.Print("  ")
+		}
+		fmt.Printf("%-12v %v (%v)\n", installation.Version, installation.Path, installation.Source)
+	}
+}
+
+func useMavenVersion(version string, profile string) error {
+	installations, _ := discoverMavenInstallations(profiles.ExecCmdProvider)
+	installed := false
+	for _, installation := range installations {
+		if matchesVersion(installation.Version, version) {
+			installed = true
+			break
+		}
+	}
+	if !installed {
+		fmt.Printf("Warning: maven version %v is not installed\n", version)
+	}
+
+	if profile == "" {
+		err := profiles.PinMavenVersion(version)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Pinned maven version %v\n", version)
+		return nil
+	}
+
+	err := profiles.SetMavenVersion(profile, version)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Pinned maven version %v for profile %v\n", version, profile)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(mavenCmd)
+	mavenCmd.AddCommand(mavenLsCmd)
+	mavenCmd.AddCommand(mavenUseCmd)
+	mavenCmd.AddCommand(mavenClearCmd)
+	mavenUseCmd.Flags().StringVarP(&mavenProfile, "profile", "p", "", "pin the version for the given profile instead of this folder")
+	mavenClearCmd.Flags().StringVarP(&mavenProfile, "profile", "p", "", "remove the version pinned for the given profile instead of this folder")
+	_ = mavenUseCmd.RegisterFlagCompletionFunc("profile", profiles.CustomProfileCompletion)
+	_ = mavenClearCmd.RegisterFlagCompletionFunc("profile", profiles.CustomProfileCompletion)
+}
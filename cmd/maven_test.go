@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"menv/profiles"
+	"os"
+	"testing"
+)
+
+func TestPrintMavenInstallations(t *testing.T) {
+	installations := []mavenInstallation{
+		{Version: "3.9.6", Path: "/cellar/maven/3.9.6/bin/mvn", Source: "homebrew"},
+		{Version: "3.8.8", Path: "/opt/apache-maven-3.8.8/bin/mvn", Source: "system"},
+	}
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printMavenInstallations(installations, "3.8")
+	printMavenInstallations(nil, "")
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, output, "  3.9.6        /cellar/maven/3.9.6/bin/mvn (homebrew)")
+	assert.Contains(t, output, "* 3.8.8        /opt/apache-maven-3.8.8/bin/mvn (system)")
+
+	installations = append(installations, mavenInstallation{Version: "4.0.0", Path: "/usr/share/maven/bin/mvn", Source: "path"})
+	r, w, _ = os.Pipe()
+	os.Stdout = w
+
+	printMavenInstallations(installations, "")
+	_ = w.Close()
+
+	result, _ = io.ReadAll(r)
+	os.Stdout = stdout
+
+	// sorted by version, with the first installation in discovery order selected
+	assert.Equal(t, "Available maven installations:\n"+
+		"  4.0.0        /usr/share/maven/bin/mvn (path)\n"+
+		"* 3.9.6        /cellar/maven/3.9.6/bin/mvn (homebrew)\n"+
+		"  3.8.8        /opt/apache-maven-3.8.8/bin/mvn (system)\n", string(result))
+	assert.Contains(t, output, "No maven installations found")
+}
+
+func TestUseMavenVersion(t *testing.T) {
+	initMvnTest(t)
+	t.Setenv("MENV_DISCOVERY", "maven_home")
+	t.Setenv("MAVEN_HOME", "")
+	t.Setenv("M2_HOME", "")
+	_ = profiles.Create("test")
+
+	assert.NoError(t, useMavenVersion("3.9.6", ""))
+	version, path := profiles.PinnedMavenVersion("test")
+	assert.Equal(t, "3.9.6", version)
+	assert.FileExists(t, path)
+
+	profiles.UnpinMavenVersion()
+	assert.NoError(t, useMavenVersion("3.8.8", "test"))
+	version, _ = profiles.PinnedMavenVersion("test")
+	assert.Equal(t, "3.8.8", version)
+
+	assert.EqualError(t, useMavenVersion("3.8.8", "unknown"), "profile unknown does not exist")
+}
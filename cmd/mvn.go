@@ -1,17 +1,12 @@
 package cmd
 
 import (
-	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
-	"io/fs"
 	"menv/color"
 	"menv/config"
 	"menv/profiles"
 	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
 )
 
 // mvnCmd represents the mvn command
@@ -20,114 +15,173 @@ var mvnCmd = &cobra.Command{
 	Hidden:             true,
 	DisableFlagParsing: true,
 	Short:              "Execute a command with maven",
-	Long:               `This command will execute a command with maven.`,
+	Long:               `This command will execute a command with maven and exit with the exit status of maven.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		execMvn(args, profiles.ExecCmdProvider)
+		if code := execMvn(args, profiles.ExecCmdProvider); code != 0 {
+			os.Exit(code)
+		}
 	},
 }
 
-func execMvn(args []string, shell func(string, ...string) profiles.ShellCommand) {
-	mvnArgs := make([]string, 0)
+// execMvn executes maven with the settings of the active profile and returns the exit status of maven.
+func execMvn(args []string, shell func(string, ...string) profiles.ShellCommand) int {
 	profile, _ := profiles.Active()
-	opts := setMavenOpts(profile)
-	if profiles.Exists(profile) {
-		file := profiles.File(profile)
-		mvnArgs = []string{"--settings", file, "--global-settings", file}
-		mvnArgs = append(mvnArgs, args...)
-	} else {
-		mvnArgs = args
+
+	lock, err := unlockProfiles([]string{profile}, true, os.Stdin)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer func() {
+		if err := lock(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	profileArgs, temporary, cleanup, err := profileMavenArgs(profile, shell)
+	if err != nil {
+		fmt.Println(err)
+		return 1
 	}
+	defer cleanup()
+	mvnArgs := append(profileArgs, args...)
 
 	mvn, err := findMaven(shell)
 
 	if err != nil {
 		fmt.Println(err)
-		return
+		return 1
 	}
+
+	env, hasEnv, err := profileEnviron(profile)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	printProfile(profile, mavenOpts(env))
+	printMaven(mvn)
+
+	if config.Exec() && !temporary {
+		// only returns when replacing the process is not possible
+		_ = execReplace(mvn, mvnArgs, env)
+	}
+
 	cmd := shell(mvn, mvnArgs...)
 
+	if hasEnv {
+		cmd.Env(env)
+	}
 	cmd.Stdin(os.Stdin)
 	cmd.Stdout(os.Stdout)
 	cmd.Stderr(os.Stderr)
-	printProfile(profile, opts)
-	_ = cmd.Run()
+	return profiles.ExitCode(cmd.Run())
 }
 
-func findMaven(shell func(string, ...string) profiles.ShellCommand) (string, error) {
-	env, b := os.LookupEnv("MENV_DISABLE_WRAPPER")
-	var disabled bool
-	if b {
-		parsed, err := strconv.ParseBool(env)
-		if err != nil {
-			return "", errors.New("MENV_DISABLE_WRAPPER is not a boolean value")
-		}
-		disabled = parsed
+// profileMavenArgs returns the arguments that make maven use the settings of the given profile, and whether they
+// refer to temporary files, which are removed by calling the returned cleanup function. Without an existing profile
+// no arguments are returned.
+func profileMavenArgs(profile string, shell func(string, ...string) profiles.ShellCommand) ([]string, bool, func(), error) {
+	if !profiles.Exists(profile) {
+		return []string{}, false, func() {}, nil
 	}
 
-	_, err := os.Stat("mvnw")
-	wrapperFound := !os.IsNotExist(err)
-
-	if wrapperFound && !disabled {
-		return findMvnWrapper()
+	file, cleanup, err := profiles.ResolvedSettingsFile(profile, shell)
+	if err != nil {
+		return nil, false, func() {}, err
 	}
+	// the files of an encrypted profile are wiped after maven exits as well
+	temporary := profiles.Encrypted(profile) || file != profiles.File(profile)
 
-	return findMvnInCellar(shell)
-}
-
-func findMvnWrapper() (string, error) {
-	return "./mvnw", nil
+	args := []string{"--settings", file, "--global-settings", file}
+	if toolchains := profiles.Toolchains(profile); toolchains != "" {
+		args = append(args, "--toolchains", toolchains)
+	}
+	if security := profiles.Security(profile); security != "" {
+		args = append(args, "-Dsettings.security="+security)
+	}
+	if profiles.Isolated(profile) && profiles.SettingsLocalRepository(file) == "" {
+		args = append(args, "-Dmaven.repo.local="+profiles.RepositoryDir(profile))
+	}
+	return args, temporary, cleanup, nil
 }
 
-func findMvnInCellar(shell func(string, ...string) profiles.ShellCommand) (string, error) {
-	cmd, _ := shell("brew", "--cellar").Output()
-	cellar := string(cmd)
-	cellar = strings.ReplaceAll(cellar, "\n", "")
-	cellar = strings.ReplaceAll(cellar, "\r", "")
-	cellar = filepath.Join(cellar, "maven")
+func findMaven(shell func(string, ...string) profiles.ShellCommand) (string, error) {
+	disabled, err := config.DisableWrapper()
+	if err != nil {
+		return "", err
+	}
 
-	if _, err := os.Stat(cellar); os.IsNotExist(err) {
-		return "", errors.New("could not find maven in (home)brew cellar")
+	if wrapper := findWrapper(); wrapper != "" && !disabled {
+		return findMvnWrapper(wrapper)
 	}
 
-	mvn := ""
+	version, _ := profiles.PinnedMavenVersion(activeProfile())
+	return discoverMaven(shell, version)
+}
 
-	_ = filepath.WalkDir(cellar, func(path string, d fs.DirEntry, err error) error {
-		if !d.IsDir() && d.Name() == "mvn" && !strings.Contains(path, "libexec") {
-			mvn = path
-			return nil
+// profileEnviron returns the environment maven should be executed with for the given profile: the environment of
+// menv with the MAVEN_OPTS, the JDK and the environment variables of the profile applied. The boolean reports whether
+// the profile changed the environment.
+func profileEnviron(profile string) ([]string, bool, error) {
+	env := os.Environ()
+	if !profiles.Exists(profile) {
+		return env, false, nil
+	}
 
+	env, changed := applyMavenOpts(env, profile)
+	if java := profiles.Java(profile); java != "" {
+		selected, err := resolveJdk(java)
+		if err != nil {
+			return nil, false, err
 		}
-		return nil
-	})
+		env = applyJdk(env, selected)
+		changed = true
+	}
 
-	if mvn == "" {
-		return "", errors.New("could not find maven in (home)brew cellar")
+	if profiles.HasEnv(profile) {
+		var err error
+		env, err = profiles.Environ(profile, env)
+		if err != nil {
+			return nil, false, err
+		}
+		changed = true
 	}
 
-	return mvn, nil
+	return env, changed, nil
 }
 
-func setMavenOpts(profile string) string {
-	if profiles.Exists(profile) && profiles.MvnOptsExists(profile) {
-		opts := profiles.MvnOpts(profile)
-		if opts == "" {
-			_ = os.Unsetenv("MAVEN_OPTS")
-			return opts
-		}
-		_ = os.Setenv("MAVEN_OPTS", opts)
-		return opts
+// activeProfile returns the active profile, if it exists.
+func activeProfile() string {
+	profile, _ := profiles.Active()
+	if !profiles.Exists(profile) {
+		return ""
 	}
+	return profile
+}
 
-	env, exists := os.LookupEnv("MAVEN_OPTS")
-	if exists {
-		return env
+// applyMavenOpts sets the MAVEN_OPTS of the given profile in the given environment, or removes MAVEN_OPTS when the
+// profile has empty MAVEN_OPTS. The boolean reports whether the profile has MAVEN_OPTS.
+func applyMavenOpts(env []string, profile string) ([]string, bool) {
+	if !profiles.Exists(profile) || !profiles.MvnOptsExists(profile) {
+		return env, false
 	}
 
-	return ""
+	values := environMap(env)
+	if opts := profiles.MvnOpts(profile); opts != "" {
+		values["MAVEN_OPTS"] = opts
+	} else {
+		delete(values, "MAVEN_OPTS")
+	}
+	return environList(values), true
+}
+
+// mavenOpts returns the MAVEN_OPTS of the given environment.
+func mavenOpts(env []string) string {
+	return environMap(env)["MAVEN_OPTS"]
 }
 
 func printProfile(profile, opts string) {
-	profiles.Profiles()
 	if !config.Verbose() {
 		return
 	}
@@ -153,6 +207,18 @@ func printProfile(profile, opts string) {
 	fmt.Println()
 }
 
+func printMaven(mvn string) {
+	if !config.Verbose() {
+		return
+	}
+
+	fmt.Print("[")
+	fmt.Print(color.Format(color.BLUE, "MENV"))
+	fmt.Print("] Using maven [")
+	fmt.Print(color.Format(color.GREEN, mvn))
+	fmt.Println("]")
+}
+
 func init() {
 	rootCmd.AddCommand(mvnCmd)
 }
@@ -9,6 +9,7 @@ import (
 	"menv/profiles"
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 )
 
@@ -33,6 +34,10 @@ func (m MockShellCommand) Stderr(stderr io.Writer) {
 	m.Called(stderr)
 }
 
+func (m MockShellCommand) Env(env []string) {
+	m.Called(env)
+}
+
 func (m MockShellCommand) Output() ([]byte, error) {
 	args := m.Called()
 	return args.Get(0).([]byte), args.Error(1)
@@ -131,10 +136,9 @@ func TestFindMvnWithWrapper(t *testing.T) {
 	_ = os.Unsetenv("MENV_DISABLE_WRAPPER")
 	initMvnTest(t)
 
-	tempDir := t.TempDir()
+	tempDir, _ := filepath.EvalSymlinks(t.TempDir())
 	_ = os.Chdir(tempDir)
-	wrapper := "mvnw"
-	_, _ = os.Create(wrapper)
+	expected := createWrapper(t, tempDir)
 
 	mockShell := MockShellCommand{
 		Mock: &mock.Mock{},
@@ -144,7 +148,6 @@ func TestFindMvnWithWrapper(t *testing.T) {
 		return &mockShell
 	}
 
-	expected := "./mvnw"
 	actual, err := findMaven(mockProvider)
 	assert.NoError(t, err)
 	assert.Equal(t, expected, actual)
@@ -169,47 +172,54 @@ func TestFindMavenInvalidEnv(t *testing.T) {
 	mockShell.AssertExpectations(t)
 }
 
-func TestSetMavenOptsNonExistentWithEnv(t *testing.T) {
+func TestApplyMavenOptsNonExistent(t *testing.T) {
 	initMvnTest(t)
 
-	expected := "-Xmx2g"
-	_ = os.Setenv("MAVEN_OPTS", expected)
-
-	actual := setMavenOpts("non_existent")
-	assert.Equal(t, expected, actual)
+	env := []string{"MAVEN_OPTS=-Xmx2g"}
+	actual, changed := applyMavenOpts(env, "non_existent")
+	assert.Equal(t, env, actual)
+	assert.False(t, changed)
+	assert.Equal(t, "-Xmx2g", mavenOpts(actual))
 }
 
-func TestSetMavenOptsNonExistentWithoutEnv(t *testing.T) {
+func TestApplyMavenOptsWithoutOpts(t *testing.T) {
 	initMvnTest(t)
+	_ = profiles.Create("test")
 
-	_ = os.Unsetenv("MAVEN_OPTS")
-	actual := setMavenOpts("non_existent")
-	assert.Empty(t, actual)
+	env := []string{"MAVEN_OPTS=-Xmx1g"}
+	actual, changed := applyMavenOpts(env, "test")
+	assert.Equal(t, env, actual)
+	assert.False(t, changed)
 }
 
-func TestSetMavenOpts(t *testing.T) {
+func TestApplyMavenOpts(t *testing.T) {
 	initMvnTest(t)
 
-	_ = os.Unsetenv("MAVEN_OPTS")
+	t.Setenv("MAVEN_OPTS", "-Xmx1g")
 	expected := "-Xmx2g"
 	profile := "test"
 	_ = profiles.Create(profile)
 	_ = os.WriteFile(profiles.OptsFile(profile), []byte(expected), 0644)
 
-	actual := setMavenOpts(profile)
-	assert.Equal(t, expected, actual)
+	actual, changed := applyMavenOpts([]string{"PATH=/usr/bin", "MAVEN_OPTS=-Xmx1g"}, profile)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"MAVEN_OPTS=-Xmx2g", "PATH=/usr/bin"}, actual)
+	assert.Equal(t, expected, mavenOpts(actual))
+	// the environment of menv itself is not changed
+	assert.Equal(t, "-Xmx1g", os.Getenv("MAVEN_OPTS"))
 }
 
-func TestSetMavenOptsEmpty(t *testing.T) {
+func TestApplyMavenOptsEmpty(t *testing.T) {
 	initMvnTest(t)
 
-	_ = os.Unsetenv("MAVEN_OPTS")
 	profile := "test"
 	_ = profiles.Create(profile)
 	_ = os.WriteFile(profiles.OptsFile(profile), []byte(""), 0644)
 
-	actual := setMavenOpts(profile)
-	assert.Empty(t, actual)
+	actual, changed := applyMavenOpts([]string{"PATH=/usr/bin", "MAVEN_OPTS=-Xmx1g"}, profile)
+	assert.True(t, changed)
+	assert.Equal(t, []string{"PATH=/usr/bin"}, actual)
+	assert.Empty(t, mavenOpts(actual))
 }
 
 func TestExecMvnNoMvn(t *testing.T) {
@@ -224,7 +234,8 @@ func TestExecMvnNoMvn(t *testing.T) {
 	}
 
 	mockShell.On("Output").Return([]byte{}, errors.New("could not find maven in (home)brew cellar"))
-	execMvn([]string{}, mockProvider)
+	code := execMvn([]string{}, mockProvider)
+	assert.Equal(t, 1, code)
 	mockShell.AssertExpectations(t)
 }
 
@@ -253,26 +264,106 @@ func TestExecMvn(t *testing.T) {
 	mockShell.On("Stdout", os.Stdout).Return()
 	mockShell.On("Stderr", os.Stderr).Return()
 	mockShell.On("Run").Return(nil)
-	execMvn([]string{}, mockProvider)
+	code := execMvn([]string{}, mockProvider)
+	assert.Equal(t, 0, code)
 	mockShell.AssertExpectations(t)
 }
 
-func TestFindMvnWrapper(t *testing.T) {
-	wrapper, err := findMvnWrapper()
+type exitError struct {
+	code int
+}
 
-	assert.NoError(t, err)
-	assert.Equal(t, "./mvnw", wrapper)
+func (e exitError) Error() string {
+	return "exit status"
+}
+
+func (e exitError) ExitCode() int {
+	return e.code
+}
+
+func TestExecMvnExitCode(t *testing.T) {
+	initMvnTest(t)
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) profiles.ShellCommand {
+		return &mockShell
+	}
+
+	tempDir := t.TempDir()
+	mvnDir := filepath.Join(tempDir, "maven", "3.9.6", "bin")
+	_ = os.MkdirAll(mvnDir, 0755)
+	_, _ = os.Create(filepath.Join(mvnDir, "mvn"))
+
+	mockShell.On("Output").Return([]byte(tempDir), nil)
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(exitError{code: 3})
+	code := execMvn([]string{"verify"}, mockProvider)
+	assert.Equal(t, 3, code)
+	mockShell.AssertExpectations(t)
+}
+
+// restoreConfig restores the configuration after the test, so the configuration of one test does not leak into others.
+func restoreConfig(t *testing.T) {
+	previous := config.Get()
+	t.Cleanup(func() {
+		config.Set(previous)
+		profiles.Init(previous)
+	})
 }
 
 func initMvnTest(t *testing.T) {
+	restoreConfig(t)
 	_ = os.Unsetenv("MENV_DISABLE_WRAPPER")
 	tempDir := t.TempDir()
 	testConfig := config.Config{
-		MenvRoot: tempDir,
-		Editor:   "vi",
+		MenvRoot:  tempDir,
+		Editor:    "vi",
+		Discovery: []string{"homebrew"},
 	}
 	config.Set(testConfig)
 	_ = config.Init()
 	profiles.Init(testConfig)
 	_ = os.Chdir(t.TempDir())
 }
+
+func TestExecMvnAppliesEnvToChildOnly(t *testing.T) {
+	initMvnTest(t)
+	_ = os.Unsetenv("MENV_TEST_TOKEN")
+
+	_ = profiles.Create("test")
+	_ = profiles.Set("test")
+	_ = profiles.SetEnv("test", "MENV_TEST_TOKEN", "secret")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) profiles.ShellCommand {
+		return &mockShell
+	}
+
+	tempDir := t.TempDir()
+	mvnDir := filepath.Join(tempDir, "maven", "3.9.6", "bin")
+	_ = os.MkdirAll(mvnDir, 0755)
+	_, _ = os.Create(filepath.Join(mvnDir, "mvn"))
+
+	mockShell.On("Output").Return([]byte(tempDir), nil)
+	mockShell.On("Env", mock.MatchedBy(func(env []string) bool {
+		return slices.Contains(env, "MENV_TEST_TOKEN=secret")
+	})).Return()
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(nil)
+	code := execMvn([]string{}, mockProvider)
+	assert.Equal(t, 0, code)
+	mockShell.AssertExpectations(t)
+
+	_, set := os.LookupEnv("MENV_TEST_TOKEN")
+	assert.False(t, set)
+}
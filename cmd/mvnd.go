@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// mvndHeapOptions maps the JVM options mvnd configures with its own properties to those properties.
+var mvndHeapOptions = map[string]string{
+	"-Xms": "mvnd.minHeapSize",
+	"-Xmx": "mvnd.maxHeapSize",
+	"-Xss": "mvnd.threadStackSize",
+}
+
+// mvndCmd represents the mvnd command
+var mvndCmd = &cobra.Command{
+	Use:                "mvnd",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Short:              "Execute a command with the maven daemon",
+	Long: `This command executes a command with the maven daemon (mvnd) and the settings of the active profile, and exits
+with the exit status of mvnd. The MAVEN_OPTS of the profile are passed to the daemon JVM, and every profile uses its own
+daemons, so a daemon started for one profile is never reused for another.
+
+mvnd is looked up in MVND_HOME, on the PATH and in SDKMAN.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if code := execMvnd(args, profiles.ExecCmdProvider); code != 0 {
+			os.Exit(code)
+		}
+	},
+}
+
+// execMvnd executes mvnd with the settings of the active profile and returns the exit status of mvnd.
+func execMvnd(args []string, shell func(string, ...string) profiles.ShellCommand) int {
+	profile, _ := profiles.Active()
+
+	lock, err := unlockProfiles([]string{profile}, true, os.Stdin)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer func() {
+		if err := lock(); err != nil {
+			fmt.Println(err)
+		}
+	}()
+
+	env, hasEnv, err := profileEnviron(profile)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	opts := mavenOpts(env)
+
+	mvndArgs, _, cleanup, err := profileMavenArgs(profile, shell)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	defer cleanup()
+	if profiles.Exists(profile) {
+		mvndArgs = append(mvndArgs, mvndOptions(opts)...)
+		mvndArgs = append(mvndArgs, "-Dmvnd.daemonStorage="+profiles.DaemonDir(profile))
+	}
+	mvndArgs = append(mvndArgs, args...)
+
+	mvnd, err := findMvnd()
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+
+	printProfile(profile, opts)
+	printMaven(mvnd)
+
+	cmd := shell(mvnd, mvndArgs...)
+
+	if hasEnv {
+		cmd.Env(env)
+	}
+	cmd.Stdin(os.Stdin)
+	cmd.Stdout(os.Stdout)
+	cmd.Stderr(os.Stderr)
+	return profiles.ExitCode(cmd.Run())
+}
+
+// mvndOptions returns the mvnd properties that pass the given MAVEN_OPTS to the daemon JVM. The heap and stack sizes
+// have their own properties, all other options are passed as mvnd.jvmArgs.
+func mvndOptions(opts string) []string {
+	result := make([]string, 0)
+	jvmArgs := make([]string, 0)
+	for _, option := range strings.Fields(opts) {
+		if len(option) > 4 {
+			if property, ok := mvndHeapOptions[option[:4]]; ok {
+				result = append(result, "-D"+property+"="+option[4:])
+				continue
+			}
+		}
+		jvmArgs = append(jvmArgs, option)
+	}
+
+	if len(jvmArgs) > 0 {
+		result = append(result, "-Dmvnd.jvmArgs="+strings.Join(jvmArgs, " "))
+	}
+	return result
+}
+
+// findMvnd returns the mvnd binary of MVND_HOME, the first one on the PATH that is not a menv shim, or the current
+// one of SDKMAN.
+func findMvnd() (string, error) {
+	if home := os.Getenv("MVND_HOME"); home != "" {
+		if mvnd := filepath.Join(home, "bin", "mvnd"); isExecutable(mvnd) {
+			return mvnd, nil
+		}
+	}
+
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		mvnd := filepath.Join(dir, "mvnd")
+		if isExecutable(mvnd) && !isMenvShim(mvnd) {
+			return mvnd, nil
+		}
+	}
+
+	if mvnd := filepath.Join(sdkmanDir(), "candidates", "mvnd", "current", "bin", "mvnd"); isExecutable(mvnd) {
+		return mvnd, nil
+	}
+	return "", errors.New("could not find mvnd in MVND_HOME, on PATH or in SDKMAN")
+}
+
+func init() {
+	rootCmd.AddCommand(mvndCmd)
+}
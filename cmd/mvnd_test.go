@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMvndOptions(t *testing.T) {
+	assert.Equal(t, []string{}, mvndOptions(""))
+	assert.Equal(t, []string{
+		"-Dmvnd.maxHeapSize=2g",
+		"-Dmvnd.minHeapSize=512m",
+		"-Dmvnd.jvmArgs=-XX:+UseG1GC -Dfile.encoding=UTF-8",
+	}, mvndOptions("-Xmx2g -XX:+UseG1GC -Xms512m -Dfile.encoding=UTF-8"))
+}
+
+func TestFindMvnd(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("MVND_HOME", home)
+	t.Setenv("PATH", "")
+	t.Setenv("SDKMAN_DIR", t.TempDir())
+
+	_, err := findMvnd()
+	assert.EqualError(t, err, "could not find mvnd in MVND_HOME, on PATH or in SDKMAN")
+
+	// the menv shim is skipped
+	shims := t.TempDir()
+	_, _ = installShims(shims, true)
+	t.Setenv("PATH", shims)
+	_, err = findMvnd()
+	assert.Error(t, err)
+
+	mvnd := filepath.Join(home, "bin", "mvnd")
+	_ = os.MkdirAll(filepath.Dir(mvnd), 0755)
+	_ = os.WriteFile(mvnd, []byte("#!/bin/sh\n"), 0755)
+	found, err := findMvnd()
+	assert.NoError(t, err)
+	assert.Equal(t, mvnd, found)
+}
+
+func TestExecMvnd(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	_ = profiles.Set("test")
+	_ = profiles.SetMvnOpts("test", "-Xmx2g -Dfoo=bar")
+	t.Setenv("MAVEN_OPTS", "")
+
+	home := t.TempDir()
+	mvnd := filepath.Join(home, "bin", "mvnd")
+	_ = os.MkdirAll(filepath.Dir(mvnd), 0755)
+	_ = os.WriteFile(mvnd, []byte("#!/bin/sh\n"), 0755)
+	t.Setenv("MVND_HOME", home)
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	var command string
+	var mvndArgs []string
+	mockProvider := func(name string, args ...string) profiles.ShellCommand {
+		command = name
+		mvndArgs = args
+		return &mockShell
+	}
+
+	mockShell.On("Env", mock.MatchedBy(func(env []string) bool {
+		return slices.Contains(env, "MAVEN_OPTS=-Xmx2g -Dfoo=bar")
+	})).Return()
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(nil)
+
+	assert.Equal(t, 0, execMvnd([]string{"verify"}, mockProvider))
+	assert.Equal(t, mvnd, command)
+	assert.Equal(t, []string{
+		"--settings", profiles.File("test"),
+		"--global-settings", profiles.File("test"),
+		"-Dmvnd.maxHeapSize=2g",
+		"-Dmvnd.jvmArgs=-Dfoo=bar",
+		"-Dmvnd.daemonStorage=" + profiles.DaemonDir("test"),
+		"verify",
+	}, mvndArgs)
+}
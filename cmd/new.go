@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
 	"menv/profiles"
 	"os"
 )
 
+var newExtends string
+
 // newCmd represents the add command
 var newCmd = &cobra.Command{
 	Use:     "new [profile]",
@@ -17,20 +20,50 @@ var newCmd = &cobra.Command{
 The profile name must be unique, cannot be empty and cannot contain spaces.
 
 The following characters are allowed (not including the comma's): a-z, A-Z, 0-9, -, and _
+
+With --extends the new profile inherits the settings of the given base profile. Servers, mirrors, proxies and
+profiles are merged by id, where the settings of the new profile win.
 `,
 	Run: func(cmd *cobra.Command, args []string) {
 		profile := args[0]
-		err := profiles.Create(profile)
+		err := createProfile(profile, newExtends)
 
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
 
+		if newExtends != "" {
+			fmt.Printf("Created profile %v extending %v\n", profile, newExtends)
+			return
+		}
 		fmt.Printf("Created profile %v\n", profile)
 	},
 }
 
+func createProfile(profile string, parent string) error {
+	if parent != "" && !profiles.Exists(parent) {
+		return errors.New(fmt.Sprintf("profile %v does not exist", parent))
+	}
+
+	err := profiles.Create(profile)
+	if err != nil {
+		return err
+	}
+
+	if parent == "" {
+		return nil
+	}
+	if err := profiles.Extend(profile, parent); err != nil {
+		// remove the half created profile, so creating it again does not fail because it already exists
+		_ = profiles.Remove(profile)
+		return err
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(newCmd)
+	newCmd.Flags().StringVar(&newExtends, "extends", "", "base profile the new profile inherits its settings from")
+	_ = newCmd.RegisterFlagCompletionFunc("extends", profiles.CustomProfileCompletion)
 }
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"menv/profiles"
+	"os"
+	"text/template"
+)
+
+var promptFormat string
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Args:  cobra.NoArgs,
+	Short: "Print the active profile for a shell prompt",
+	Long: `This command prints the active profile formatted with a Go template, for a segment of a shell prompt like starship
+or powerlevel10k. Nothing is printed when no profile is active. Only a few files are read, no other processes are
+started.
+
+The template can use these fields:
+  {{.Profile}}  the active profile
+  {{.Source}}   the .menv_profile or configuration file that selects the profile
+  {{.Java}}     the JDK of the profile, as configured
+  {{.Maven}}    the pinned maven version, if any
+
+Example:
+menv prompt --format '{{.Profile}}@{{.Source}}'`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := printPrompt(os.Stdout, promptFormat); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	},
+}
+
+// promptSegment holds the fields available to the template of the prompt command.
+type promptSegment struct {
+	Profile string
+	Source  string
+	Java    string
+	Maven   string
+}
+
+// printPrompt writes the active profile formatted with the given template, or nothing when no existing profile is
+// active.
+func printPrompt(w io.Writer, format string) error {
+	tmpl, err := template.New("prompt").Parse(format)
+	if err != nil {
+		return err
+	}
+
+	// a .menv_profile can name a profile that was removed since
+	profile, source := profiles.Active()
+	if !profiles.Exists(profile) {
+		return nil
+	}
+
+	maven, _ := profiles.PinnedMavenVersion(profile)
+	segment := promptSegment{
+		Profile: profile,
+		Source:  source,
+		Java:    profiles.Java(profile),
+		Maven:   maven,
+	}
+	return tmpl.Execute(w, segment)
+}
+
+func init() {
+	rootCmd.AddCommand(promptCmd)
+	promptCmd.Flags().StringVar(&promptFormat, "format", "{{.Profile}}", "Go template used to print the active profile")
+}
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrintPrompt(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("work")
+	_ = profiles.SetJava("work", "21")
+
+	var output strings.Builder
+	assert.NoError(t, printPrompt(&output, "{{.Profile}}"))
+	assert.Equal(t, "", output.String())
+
+	_ = profiles.Set("work")
+	dir, _ := os.Getwd()
+	assert.NoError(t, printPrompt(&output, "{{.Profile}}@{{.Source}} java {{.Java}}"))
+	assert.Equal(t, "work@"+filepath.Join(dir, ".menv_profile")+" java 21", output.String())
+
+	assert.Error(t, printPrompt(&output, "{{.Profile"))
+}
+
+func TestPrintPromptRemovedProfile(t *testing.T) {
+	initMvnTest(t)
+	_ = os.WriteFile(".menv_profile", []byte("removed\n"), 0644)
+
+	var output strings.Builder
+	assert.NoError(t, printPrompt(&output, "{{.Profile}}"))
+	assert.Equal(t, "", output.String())
+}
@@ -0,0 +1,360 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"io/fs"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	pruneOlderThan string
+	pruneDryRun    bool
+	pruneProfile   string
+)
+
+// snapshotPattern matches the timestamped files of a snapshot version, like artifact-1.0-20240101.123456-3.jar.
+var snapshotPattern = regexp.MustCompile(`^(.*)-(\d{8}\.\d{6})-(\d+)(.*)$`)
+
+// pruneOptions configure which files pruneRepository removes.
+type pruneOptions struct {
+	// olderThan removes artifact versions that have not been accessed for this duration, zero disables it.
+	olderThan time.Duration
+	dryRun    bool
+	now       time.Time
+}
+
+// pruneResult summarizes the files removed by pruneRepository, by the reason they are removed.
+type pruneResult struct {
+	Paths map[string][]string
+	Files map[string]int
+	Bytes map[string]int64
+}
+
+const (
+	pruneUnused      = "unused versions"
+	pruneSnapshots   = "stale snapshots"
+	pruneLastUpdated = "lastUpdated markers"
+	pruneOrphans     = "orphaned _remote.repositories"
+)
+
+var pruneReasons = []string{pruneUnused, pruneSnapshots, pruneLastUpdated, pruneOrphans}
+
+var repoPruneCmd = &cobra.Command{
+	Use:   "prune",
+	Args:  cobra.NoArgs,
+	Short: "Remove unused artifacts and stale files from the local repository of the active profile",
+	Long: `This command cleans up the local repository of the active profile, or the profile provided with --profile.
+The local repository is the <localRepository> of the settings.xml of the profile, the isolated repository of the
+profile, or ~/.m2/repository.
+
+The following files are removed:
+  - artifact versions that have not been accessed for the duration given with --older-than, like 30d, 2w or 12h
+  - timestamped -SNAPSHOT files, except for the latest build
+  - *.lastUpdated files, which maven leaves behind when downloading failed
+  - _remote.repositories files of versions without artifacts
+
+Use --dry-run to see what would be removed.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThan, err := parseAge(pruneOlderThan)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		repository, err := pruneTarget(pruneProfile, os.Stdin)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+
+		result, err := pruneRepository(repository, pruneOptions{olderThan: olderThan, dryRun: pruneDryRun, now: time.Now()})
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		printPruneResult(repository, result, pruneDryRun)
+	},
+}
+
+// pruneTarget returns the local repository of the given profile, or of the active profile if none is given. A
+// profile that does not exist is an error, rather than falling back to ~/.m2/repository. Encrypted profiles are
+// unlocked to read their local repository.
+func pruneTarget(profile string, in io.Reader) (string, error) {
+	if profile == "" {
+		profile = activeProfile()
+	} else if !profiles.Exists(profile) {
+		return "", errors.New(fmt.Sprintf("profile %v does not exist", profile))
+	}
+
+	lock, err := unlockProfiles([]string{profile}, true, in)
+	if err != nil {
+		return "", err
+	}
+	repository, err := localRepository(profile)
+	if lockErr := lock(); err == nil {
+		err = lockErr
+	}
+	return repository, err
+}
+
+// parseAge parses a duration like 30d, 2w or 12h. An empty string results in zero.
+func parseAge(age string) (time.Duration, error) {
+	if age == "" {
+		return 0, nil
+	}
+
+	units := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	unit := units[age[len(age)-1:]]
+	number, err := strconv.Atoi(age[:len(age)-1])
+	if unit == 0 || err != nil || number <= 0 {
+		return 0, errors.New(fmt.Sprintf("invalid age %v, use a number followed by h, d or w, like 30d", age))
+	}
+	return time.Duration(number) * unit, nil
+}
+
+// pruneRepository removes unused artifact versions, stale snapshots, lastUpdated markers and orphaned
+// _remote.repositories files from the given local repository.
+func pruneRepository(root string, options pruneOptions) (pruneResult, error) {
+	result := pruneResult{Paths: make(map[string][]string), Files: make(map[string]int), Bytes: make(map[string]int64)}
+
+	if !isDir(root) {
+		return result, errors.New(fmt.Sprintf("local repository %v does not exist", root))
+	}
+
+	versions := make([]string, 0)
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && isVersionDir(path) {
+			versions = append(versions, path)
+			return filepath.SkipDir
+		}
+		return nil
+	})
+
+	for _, version := range versions {
+		entries, err := os.ReadDir(version)
+		if err != nil {
+			continue
+		}
+
+		if options.olderThan > 0 && lastAccess(version, entries).Before(options.now.Add(-options.olderThan)) {
+			result.add(pruneUnused, version, dirSize(version), countFiles(entries))
+			if !options.dryRun {
+				_ = os.RemoveAll(version)
+			}
+			continue
+		}
+
+		removed := make(map[string]bool)
+		for _, name := range staleSnapshots(entries) {
+			removed[name] = true
+			result.remove(pruneSnapshots, filepath.Join(version, name), options.dryRun)
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".lastUpdated") {
+				removed[entry.Name()] = true
+				result.remove(pruneLastUpdated, filepath.Join(version, entry.Name()), options.dryRun)
+			}
+		}
+		if isOrphaned(entries, removed) {
+			result.remove(pruneOrphans, filepath.Join(version, "_remote.repositories"), options.dryRun)
+		}
+	}
+
+	// lastUpdated markers are also written next to maven-metadata.xml files outside version directories
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.HasSuffix(d.Name(), ".lastUpdated") && !result.contains(pruneLastUpdated, path) && !result.within(path) {
+			result.remove(pruneLastUpdated, path, options.dryRun)
+		}
+		return nil
+	})
+
+	if !options.dryRun {
+		removeEmptyDirs(root)
+	}
+	return result, nil
+}
+
+// isVersionDir reports whether the given directory contains an artifact version, which is a directory containing a
+// pom, or files named after the artifact and version.
+func isVersionDir(dir string) bool {
+	artifactId := filepath.Base(filepath.Dir(dir))
+	version := filepath.Base(dir)
+	prefix := artifactId + "-" + strings.TrimSuffix(version, "-SNAPSHOT")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(entry.Name(), prefix) || entry.Name() == "_remote.repositories" {
+			return true
+		}
+	}
+	return false
+}
+
+func lastAccess(dir string, entries []fs.DirEntry) time.Time {
+	var last time.Time
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || entry.IsDir() {
+			continue
+		}
+		if accessed := accessTime(info); accessed.After(last) {
+			last = accessed
+		}
+	}
+	if last.IsZero() {
+		if info, err := os.Stat(dir); err == nil {
+			return info.ModTime()
+		}
+	}
+	return last
+}
+
+// staleSnapshots returns the timestamped snapshot files that are superseded by a later build of the same file.
+func staleSnapshots(entries []fs.DirEntry) []string {
+	builds := make(map[string][]string)
+	for _, entry := range entries {
+		match := snapshotPattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		key := match[1] + match[4]
+		builds[key] = append(builds[key], entry.Name())
+	}
+
+	stale := make([]string, 0)
+	for _, names := range builds {
+		sort.Slice(names, func(i, j int) bool {
+			return snapshotBuild(names[i]) > snapshotBuild(names[j])
+		})
+		stale = append(stale, names[1:]...)
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// snapshotBuild returns a sortable representation of the timestamp and build number of a snapshot file.
+func snapshotBuild(name string) string {
+	match := snapshotPattern.FindStringSubmatch(name)
+	number, _ := strconv.Atoi(match[3])
+	return fmt.Sprintf("%v-%010d", match[2], number)
+}
+
+// isOrphaned reports whether the version has a _remote.repositories file, but no artifacts left.
+func isOrphaned(entries []fs.DirEntry, removed map[string]bool) bool {
+	remote := false
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case name == "_remote.repositories":
+			remote = true
+		case entry.IsDir() || removed[name] || strings.HasPrefix(name, "maven-metadata") || name == "resolver-status.properties":
+		default:
+			return false
+		}
+	}
+	return remote
+}
+
+func countFiles(entries []fs.DirEntry) int {
+	count := 0
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			count++
+		}
+	}
+	return count
+}
+
+func removeEmptyDirs(root string) {
+	dirs := make([]string, 0)
+	_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && path != root {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		_ = os.Remove(dirs[i])
+	}
+}
+
+func (r pruneResult) add(reason string, path string, size int64, files int) {
+	r.Paths[reason] = append(r.Paths[reason], path)
+	r.Files[reason] += files
+	r.Bytes[reason] += size
+}
+
+func (r pruneResult) remove(reason string, path string, dryRun bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	r.add(reason, path, info.Size(), 1)
+	if !dryRun {
+		_ = os.Remove(path)
+	}
+}
+
+func (r pruneResult) contains(reason string, path string) bool {
+	for _, p := range r.Paths[reason] {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
+
+// within reports whether the given path is part of a removed artifact version.
+func (r pruneResult) within(path string) bool {
+	for _, dir := range r.Paths[pruneUnused] {
+		if strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return true
+		}
+	}
+	return false
+}
+
+func printPruneResult(repository string, result pruneResult, dryRun bool) {
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+		for _, reason := range pruneReasons {
+			for _, path := range result.Paths[reason] {
+				fmt.Printf("  %v\n", path)
+			}
+		}
+	}
+
+	fmt.Printf("Local repository %v:\n", repository)
+	var total int64
+	for _, reason := range pruneReasons {
+		fmt.Printf("  %-30v %6d files %10v\n", reason, result.Files[reason], formatBytes(result.Bytes[reason]))
+		total += result.Bytes[reason]
+	}
+	fmt.Printf("%v %v in total\n", verb, formatBytes(total))
+}
+
+func init() {
+	repoCmd.AddCommand(repoPruneCmd)
+	repoPruneCmd.Flags().StringVar(&pruneOlderThan, "older-than", "", "remove artifact versions not accessed for this duration, like 30d, 2w or 12h")
+	repoPruneCmd.Flags().BoolVar(&pruneDryRun, "dry-run", false, "only show what would be removed")
+	repoPruneCmd.Flags().StringVarP(&pruneProfile, "profile", "p", "", "profile to use instead of the active profile")
+	_ = repoPruneCmd.RegisterFlagCompletionFunc("profile", profiles.CustomProfileCompletion)
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createArtifact(t *testing.T, root string, version string, files map[string]int, accessed time.Time) string {
+	t.Helper()
+	dir := filepath.Join(root, "com", "acme", "lib", version)
+	_ = os.MkdirAll(dir, 0755)
+	for name, size := range files {
+		path := filepath.Join(dir, name)
+		_ = os.WriteFile(path, make([]byte, size), 0644)
+		_ = os.Chtimes(path, accessed, accessed)
+	}
+	return dir
+}
+
+func TestParseAge(t *testing.T) {
+	age, err := parseAge("30d")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, age)
+
+	age, _ = parseAge("2w")
+	assert.Equal(t, 14*24*time.Hour, age)
+
+	age, _ = parseAge("")
+	assert.Zero(t, age)
+
+	_, err = parseAge("30x")
+	assert.EqualError(t, err, "invalid age 30x, use a number followed by h, d or w, like 30d")
+	_, err = parseAge("d")
+	assert.Error(t, err)
+}
+
+func TestPruneRepository(t *testing.T) {
+	root := t.TempDir()
+	now := time.Now()
+	old := now.Add(-60 * 24 * time.Hour)
+
+	unused := createArtifact(t, root, "1.0", map[string]int{"lib-1.0.jar": 1000, "lib-1.0.pom": 100, "_remote.repositories": 10}, old)
+	used := createArtifact(t, root, "2.0", map[string]int{"lib-2.0.jar": 1000, "lib-2.0.pom": 100, "lib-2.0.jar.lastUpdated": 50}, now)
+	snapshot := createArtifact(t, root, "3.0-SNAPSHOT", map[string]int{
+		"lib-3.0-20240101.120000-1.jar":  500,
+		"lib-3.0-20240102.120000-2.jar":  500,
+		"lib-3.0-20240102.120000-10.jar": 500,
+		"lib-3.0-20240102.120000-10.pom": 50,
+		"lib-3.0-SNAPSHOT.jar":           500,
+	}, now)
+	orphan := createArtifact(t, root, "4.0", map[string]int{"_remote.repositories": 10, "lib-4.0.pom.lastUpdated": 20}, now)
+	_ = os.WriteFile(filepath.Join(root, "com", "acme", "lib", "maven-metadata-central.xml.lastUpdated"), make([]byte, 5), 0644)
+
+	options := pruneOptions{olderThan: 30 * 24 * time.Hour, dryRun: true, now: now}
+	result, err := pruneRepository(root, options)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{unused}, result.Paths[pruneUnused])
+	assert.Equal(t, 3, result.Files[pruneUnused])
+	assert.Equal(t, int64(1110), result.Bytes[pruneUnused])
+	assert.Equal(t, []string{
+		filepath.Join(snapshot, "lib-3.0-20240101.120000-1.jar"),
+		filepath.Join(snapshot, "lib-3.0-20240102.120000-2.jar"),
+	}, result.Paths[pruneSnapshots])
+	assert.Equal(t, 3, result.Files[pruneLastUpdated])
+	assert.Equal(t, []string{filepath.Join(orphan, "_remote.repositories")}, result.Paths[pruneOrphans])
+	assert.DirExists(t, unused)
+
+	options.dryRun = false
+	_, err = pruneRepository(root, options)
+	assert.NoError(t, err)
+	assert.NoDirExists(t, unused)
+	assert.NoDirExists(t, orphan)
+	assert.FileExists(t, filepath.Join(used, "lib-2.0.jar"))
+	assert.NoFileExists(t, filepath.Join(used, "lib-2.0.jar.lastUpdated"))
+	assert.FileExists(t, filepath.Join(snapshot, "lib-3.0-20240102.120000-10.jar"))
+	assert.FileExists(t, filepath.Join(snapshot, "lib-3.0-SNAPSHOT.jar"))
+	assert.NoFileExists(t, filepath.Join(snapshot, "lib-3.0-20240101.120000-1.jar"))
+	assert.NoFileExists(t, filepath.Join(root, "com", "acme", "lib", "maven-metadata-central.xml.lastUpdated"))
+}
+
+func TestPruneRepositoryWithoutAge(t *testing.T) {
+	root := t.TempDir()
+	old := time.Now().Add(-365 * 24 * time.Hour)
+	unused := createArtifact(t, root, "1.0", map[string]int{"lib-1.0.jar": 1000}, old)
+
+	result, err := pruneRepository(root, pruneOptions{now: time.Now()})
+	assert.NoError(t, err)
+	assert.Empty(t, result.Paths[pruneUnused])
+	assert.DirExists(t, unused)
+
+	_, err = pruneRepository(filepath.Join(root, "missing"), pruneOptions{})
+	assert.EqualError(t, err, "local repository "+filepath.Join(root, "missing")+" does not exist")
+}
+
+func TestPruneTarget(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	_ = profiles.SetIsolated("test", true)
+
+	repository, err := pruneTarget("test", strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Equal(t, profiles.RepositoryDir("test"), repository)
+
+	_, err = pruneTarget("tset", strings.NewReader(""))
+	assert.EqualError(t, err, "profile tset does not exist")
+}
+
+func TestPruneTargetEncrypted(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	_ = profiles.SetIsolated("test", true)
+	keyFile := filepath.Join(t.TempDir(), "test.key")
+	_ = os.WriteFile(keyFile, []byte("key"), 0600)
+	_ = profiles.Encrypt("test", nil, keyFile)
+
+	repository, err := pruneTarget("test", strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Equal(t, profiles.RepositoryDir("test"), repository)
+	assert.True(t, profiles.Locked("test"))
+
+	_ = os.Remove(keyFile)
+	_, err = pruneTarget("test", strings.NewReader(""))
+	assert.ErrorContains(t, err, "could not read key file of profile test")
+}
+
+func TestPrintPruneResult(t *testing.T) {
+	result := pruneResult{
+		Paths: map[string][]string{pruneSnapshots: {"/repo/lib-1.0-20240101.120000-1.jar"}},
+		Files: map[string]int{pruneSnapshots: 1},
+		Bytes: map[string]int64{pruneSnapshots: 2048},
+	}
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printPruneResult("/repo", result, true)
+	_ = w.Close()
+
+	output, _ := io.ReadAll(r)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, string(output), "  /repo/lib-1.0-20240101.120000-1.jar\n")
+	assert.Contains(t, string(output), "Would remove 2.0 KiB in total")
+}
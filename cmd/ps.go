@@ -25,7 +25,18 @@ func printActiveProfile(profile string, path string) {
 		return
 	}
 
-	fmt.Printf("  %v (set by %v)\n", profile, path)
+	fmt.Printf("  %v (set by %v)\n", describeProfile(profile), path)
+
+	if !profiles.Exists(profile) {
+		return
+	}
+	if java := profiles.Java(profile); java != "" {
+		if selected, err := resolveJdk(java); err == nil {
+			fmt.Printf("  JDK %v (%v)\n", selected.Version, selected.Home)
+		} else {
+			fmt.Printf("  JDK %v (not installed)\n", java)
+		}
+	}
 }
 
 func init() {
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"menv/profiles"
+	"os"
+	"strings"
+)
+
+// promptSecret prints the prompt and reads a line from reader, which reads from in. When in is a terminal, the
+// entered value is not echoed.
+func promptSecret(reader *bufio.Reader, in io.Reader, prompt string) (string, error) {
+	fmt.Print(prompt)
+	restore := hideInput(in)
+	line, err := reader.ReadString('\n')
+	restore()
+
+	if err != nil && (err != io.EOF || line == "") {
+		return "", errors.New("no value entered")
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// hideInput turns off the echo of the given terminal, and returns a function that turns it on again.
+func hideInput(in io.Reader) func() {
+	file, ok := in.(*os.File)
+	if !ok {
+		return func() {}
+	}
+	info, err := file.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return func() {}
+	}
+
+	if stty(file, "-echo") != nil {
+		return func() {}
+	}
+	return func() {
+		_ = stty(file, "echo")
+		fmt.Println()
+	}
+}
+
+func stty(terminal *os.File, arg string) error {
+	cmd := profiles.ExecCmdProvider("stty", arg)
+	cmd.Stdin(terminal)
+	return cmd.Run()
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+)
+
+// remoteCmd represents the remote command
+var remoteCmd = &cobra.Command{
+	Use:   "remote",
+	Short: "Manage git repositories with shared profiles",
+	Long: `With this command you can use profiles that are maintained in a git repository, for example by a platform team.
+
+The repository is cloned into ~/.config/menv/remotes/<remote>. It contains profiles in the same layout as
+~/.config/menv, like settings.xml.<profile> and <profile>.maven_opts. Its profiles are available as <remote>/<profile>
+and are read-only, a local profile can extend them with 'menv new <profile> --extends <remote>/<profile>'.`,
+}
+
+var remoteAddCmd = &cobra.Command{
+	Use:   "add [remote] [git-url]",
+	Args:  cobra.ExactArgs(2),
+	Short: "Clone a git repository with shared profiles",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := profiles.AddRemote(args[0], args[1], profiles.ExecCmdProvider)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Added remote %v\n", args[0])
+	},
+}
+
+var remotePullCmd = &cobra.Command{
+	Use:               "pull [remote]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: remoteCompletion,
+	Short:             "Fetch the latest profiles of the provided remote, or all remotes if none is provided",
+	Run: func(cmd *cobra.Command, args []string) {
+		remotes := args
+		if len(remotes) == 0 {
+			remotes = profiles.Remotes()
+		}
+
+		for _, remote := range remotes {
+			err := profiles.PullRemote(remote, profiles.ExecCmdProvider)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+			fmt.Printf("Updated remote %v\n", remote)
+		}
+	},
+}
+
+var remoteStatusCmd = &cobra.Command{
+	Use:   "status",
+	Args:  cobra.NoArgs,
+	Short: "Show whether the local copies of the remotes are up to date",
+	Run: func(cmd *cobra.Command, args []string) {
+		printRemoteStatus(profiles.Remotes(), profiles.ExecCmdProvider)
+	},
+}
+
+var remoteRmCmd = &cobra.Command{
+	Use:               "rm [remote]",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: remoteCompletion,
+	Short:             "Remove the provided remote and its profiles",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := profiles.RemoveRemote(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Removed remote %v\n", args[0])
+	},
+}
+
+func printRemoteStatus(remotes []string, shell func(string, ...string) profiles.ShellCommand) {
+	if len(remotes) == 0 {
+		fmt.Println("No remotes found")
+		return
+	}
+
+	for _, remote := range remotes {
+		status, err := profiles.Status(remote, shell)
+		if err != nil {
+			fmt.Println(err)
+			continue
+		}
+
+		state := "up to date"
+		switch {
+		case status.Behind > 0 && status.Ahead > 0:
+			state = fmt.Sprintf("diverged, %v commits behind and %v ahead", status.Behind, status.Ahead)
+		case status.Behind > 0:
+			state = fmt.Sprintf("%v commits behind, run 'menv remote pull %v'", status.Behind, remote)
+		case status.Ahead > 0:
+			state = fmt.Sprintf("%v commits ahead", status.Ahead)
+		}
+		fmt.Printf("%v (%v): %v\n", remote, status.Url, state)
+	}
+}
+
+func remoteCompletion(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return profiles.Remotes(), cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(remoteCmd)
+	remoteCmd.AddCommand(remoteAddCmd)
+	remoteCmd.AddCommand(remotePullCmd)
+	remoteCmd.AddCommand(remoteStatusCmd)
+	remoteCmd.AddCommand(remoteRmCmd)
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io"
+	"menv/profiles"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrintRemoteStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	initMvnTest(t)
+	url := filepath.Join(t.TempDir(), "profiles.git")
+	work := filepath.Join(t.TempDir(), "work")
+	for _, args := range [][]string{
+		{"init", "--quiet", "--bare", url},
+		{"clone", "--quiet", url, work},
+		{"-C", work, "-c", "user.name=menv", "-c", "user.email=menv@example.com", "commit", "--quiet", "--allow-empty", "-m", "init"},
+		{"-C", work, "push", "--quiet", "origin", "HEAD"},
+	} {
+		out, err := exec.Command("git", args...).CombinedOutput()
+		assert.NoError(t, err, string(out))
+	}
+	_ = profiles.AddRemote("team", url, profiles.ExecCmdProvider)
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printRemoteStatus([]string{}, profiles.ExecCmdProvider)
+	printRemoteStatus([]string{"team", "unknown"}, profiles.ExecCmdProvider)
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, output, "No remotes found\n")
+	assert.Contains(t, output, "team ("+url+"): up to date\n")
+	assert.Contains(t, output, "remote unknown does not exist\n")
+}
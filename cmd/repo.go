@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"io/fs"
+	"menv/profiles"
+	"path/filepath"
+)
+
+// repoCmd represents the repo command
+var repoCmd = &cobra.Command{
+	Use:   "repo",
+	Short: "Manage the local maven repositories of the profiles",
+	Long: `With this command you can manage the local maven repositories used by the profiles.
+
+By default all profiles share the local repository declared in their settings.xml, or ~/.m2/repository. A profile can
+opt into an isolated local repository, which is stored in ~/.config/menv/repositories/<profile>. When a profile uses
+an isolated repository, maven is executed with -Dmaven.repo.local pointing to it, unless the settings.xml of the
+profile declares a <localRepository>.`,
+}
+
+var repoIsolateCmd = &cobra.Command{
+	Use:               "isolate [profile]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Use an isolated local repository for the provided profile, or the active profile if none is provided",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(firstArg(args))
+		if profile == "" {
+			return
+		}
+
+		err := profiles.SetIsolated(profile, true)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Profile %v uses local repository %v\n", profile, profiles.RepositoryDir(profile))
+	},
+}
+
+var repoShareCmd = &cobra.Command{
+	Use:               "share [profile]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Use the shared local repository for the provided profile, or the active profile if none is provided",
+	Long: `This command makes the profile use the shared local repository again. The isolated repository is kept, remove
+it manually to free its disk space.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(firstArg(args))
+		if profile == "" {
+			return
+		}
+
+		err := profiles.SetIsolated(profile, false)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Profile %v uses the shared local repository\n", profile)
+	},
+}
+
+var repoDuCmd = &cobra.Command{
+	Use:   "du",
+	Args:  cobra.NoArgs,
+	Short: "Show the disk usage of the local repository of every profile",
+	Long: `This command shows the disk usage of the local repository of every profile. Encrypted profiles are not
+unlocked, their local repository is not shown.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		printRepositoryUsage(profiles.Profiles())
+	},
+}
+
+// localRepository returns the local repository maven uses for the given profile. The settings of an encrypted profile,
+// and of the profiles it extends, cannot be read unless they are unlocked.
+func localRepository(profile string) (string, error) {
+	if !profiles.Exists(profile) {
+		return profiles.DefaultRepositoryDir(), nil
+	}
+
+	file, cleanup, err := profiles.SettingsFile(profile)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	if repository := profiles.SettingsLocalRepository(file); repository != "" {
+		return repository, nil
+	}
+	if profiles.Isolated(profile) {
+		return profiles.RepositoryDir(profile), nil
+	}
+	return profiles.DefaultRepositoryDir(), nil
+}
+
+func printRepositoryUsage(profileList []string) {
+	if len(profileList) == 0 {
+		fmt.Println("No profiles found")
+		return
+	}
+
+	sizes := make(map[string]int64)
+	fmt.Println("Local repositories:")
+	for _, profile := range profileList {
+		repository, err := localRepository(profile)
+		if err != nil {
+			fmt.Printf("  %-20v %10v  %v\n", profile, "-", err)
+			continue
+		}
+		size, ok := sizes[repository]
+		if !ok {
+			size = dirSize(repository)
+			sizes[repository] = size
+		}
+		fmt.Printf("  %-20v %10v  %v\n", profile, formatBytes(size), repository)
+	}
+}
+
+// dirSize returns the total size of the files in the given directory.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+	return size
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+func init() {
+	rootCmd.AddCommand(repoCmd)
+	repoCmd.AddCommand(repoIsolateCmd)
+	repoCmd.AddCommand(repoShareCmd)
+	repoCmd.AddCommand(repoDuCmd)
+}
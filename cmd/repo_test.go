@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecMvnIsolatedRepository(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	_ = profiles.Set("test")
+	_ = profiles.SetIsolated("test", true)
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	var mvnArgs []string
+	mockProvider := func(command string, args ...string) profiles.ShellCommand {
+		mvnArgs = args
+		return &mockShell
+	}
+
+	tempDir := t.TempDir()
+	mvnDir := filepath.Join(tempDir, "maven", "3.9.6", "bin")
+	_ = os.MkdirAll(mvnDir, 0755)
+	_, _ = os.Create(filepath.Join(mvnDir, "mvn"))
+
+	mockShell.On("Output").Return([]byte(tempDir), nil)
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(nil)
+
+	execMvn([]string{"verify"}, mockProvider)
+	assert.Contains(t, mvnArgs, "-Dmaven.repo.local="+profiles.RepositoryDir("test"))
+	assert.Equal(t, "verify", mvnArgs[len(mvnArgs)-1])
+
+	_ = os.WriteFile(profiles.File("test"), []byte("<settings><localRepository>/custom</localRepository></settings>"), 0644)
+	execMvn([]string{"verify"}, mockProvider)
+	assert.NotContains(t, mvnArgs, "-Dmaven.repo.local="+profiles.RepositoryDir("test"))
+}
+
+func TestLocalRepository(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("shared")
+	_ = profiles.Create("isolated")
+	_ = profiles.Create("custom")
+	_ = profiles.SetIsolated("isolated", true)
+	_ = os.WriteFile(profiles.File("custom"), []byte("<settings><localRepository>/custom</localRepository></settings>"), 0644)
+
+	for profile, expected := range map[string]string{
+		"shared":   profiles.DefaultRepositoryDir(),
+		"":         profiles.DefaultRepositoryDir(),
+		"isolated": profiles.RepositoryDir("isolated"),
+		"custom":   "/custom",
+	} {
+		repository, err := localRepository(profile)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, repository)
+	}
+
+	_ = profiles.Encrypt("isolated", []byte("passphrase"), "")
+	_, err := localRepository("isolated")
+	assert.EqualError(t, err, "profile isolated is encrypted")
+}
+
+func TestPrintRepositoryUsage(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("isolated")
+	_ = profiles.SetIsolated("isolated", true)
+	_ = os.WriteFile(filepath.Join(profiles.RepositoryDir("isolated"), "artifact.jar"), make([]byte, 2048), 0644)
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	printRepositoryUsage([]string{"isolated"})
+	printRepositoryUsage([]string{})
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Contains(t, output, "  isolated                2.0 KiB  "+profiles.RepositoryDir("isolated"))
+	assert.Contains(t, output, "No profiles found")
+}
+
+func TestFormatBytes(t *testing.T) {
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "1.5 KiB", formatBytes(1536))
+	assert.Equal(t, "3.0 GiB", formatBytes(3*1024*1024*1024))
+}
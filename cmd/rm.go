@@ -22,6 +22,7 @@ var rmCmd = &cobra.Command{
 		err := profiles.Remove(profile)
 		if err != nil {
 			fmt.Println(err)
+			return
 		}
 		fmt.Printf("Removed profile %v\n", profile)
 
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+	"strconv"
+)
+
+// rollbackCmd represents the rollback command
+var rollbackCmd = &cobra.Command{
+	Use:               "rollback [profile] [rev]",
+	Args:              cobra.RangeArgs(1, 2),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Restore the settings.xml and MAVEN_OPTS of the provided profile from its history",
+	Long: `This command restores a snapshot listed by 'menv history'. Without a revision the latest snapshot is restored,
+which undoes the last change. The state before the rollback is recorded in the history as well.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := args[0]
+		rev := 0
+		if len(args) > 1 {
+			parsed, err := strconv.Atoi(args[1])
+			if err != nil || parsed < 1 {
+				fmt.Printf("Invalid revision %v\n", args[1])
+				return
+			}
+			rev = parsed
+		}
+
+		snapshot, err := profiles.Rollback(profile, rev)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Restored revision %v of profile %v\n", snapshot.Rev, profile)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/config"
+	"menv/profiles"
+	"os"
+)
+
+// secretCmd represents the secret command
+var secretCmd = &cobra.Command{
+	Use:   "secret",
+	Short: "Manage the secrets referenced by the settings.xml of the profiles",
+	Long: `Instead of a literal password, the settings.xml of a profile can contain a reference to a secret, like
+<password>menv-secret:nexus-token</password>. This way the profile can be shared or committed without its secrets.
+
+When maven is executed, the references are resolved from the local secrets file, which is encrypted with a key stored
+in ~/.config/menv/secrets.key, or otherwise with the configured secret_command, like 'pass show {name}'. In the
+secret_command {name} is replaced by $1, which holds the name of the secret. The resolved settings are written to a
+temporary file that only you can read, which is removed after maven exits.
+
+The key is stored next to the secrets file, so anyone who can read both files can decrypt the secrets. The encryption
+only keeps the secrets from being read by accident; use a secret_command with a password manager to protect them.`,
+}
+
+var secretSetCmd = &cobra.Command{
+	Use:   "set [name] [value]",
+	Args:  cobra.RangeArgs(1, 2),
+	Short: "Store a secret in the local secrets file, the value is asked for when it is not provided",
+	Run: func(cmd *cobra.Command, args []string) {
+		name := args[0]
+		value := ""
+		if len(args) > 1 {
+			value = args[1]
+		} else {
+			var err error
+			value, err = promptSecret(bufio.NewReader(os.Stdin), os.Stdin, fmt.Sprintf("Value of secret %v: ", name))
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+
+		err := profiles.SetSecret(name, value)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Stored secret %v, reference it as %v%v\n", name, profiles.SecretPrefix, name)
+	},
+}
+
+var secretLsCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Args:    cobra.NoArgs,
+	Short:   "List the names of the secrets in the local secrets file",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := printSecrets()
+		if err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var secretRmCmd = &cobra.Command{
+	Use:               "rm [name]",
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: secretCompletion,
+	Short:             "Remove a secret from the local secrets file",
+	Run: func(cmd *cobra.Command, args []string) {
+		err := profiles.RemoveSecret(args[0])
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Removed secret %v\n", args[0])
+	},
+}
+
+func printSecrets() error {
+	names, err := profiles.Secrets()
+	if err != nil {
+		return err
+	}
+
+	if len(names) == 0 {
+		fmt.Println("No secrets stored")
+	}
+	for _, name := range names {
+		fmt.Printf("  %v\n", name)
+	}
+	if command := config.SecretCommand(); command != "" {
+		fmt.Printf("Other secrets are resolved with: %v\n", command)
+	}
+	return nil
+}
+
+func secretCompletion(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, _ := profiles.Secrets()
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func init() {
+	rootCmd.AddCommand(secretCmd)
+	secretCmd.AddCommand(secretSetCmd)
+	secretCmd.AddCommand(secretLsCmd)
+	secretCmd.AddCommand(secretRmCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"io"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestExecMvnResolvesSecrets(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	_ = profiles.Set("test")
+	_ = os.WriteFile(profiles.File("test"), []byte("<settings><servers><server><id>nexus</id><password>menv-secret:nexus-token</password></server></servers></settings>"), 0644)
+	_ = profiles.SetSecret("nexus-token", "s3cret")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	var mvnArgs []string
+	var rendered string
+	mockProvider := func(command string, args ...string) profiles.ShellCommand {
+		mvnArgs = args
+		return &mockShell
+	}
+
+	tempDir := t.TempDir()
+	mvnDir := filepath.Join(tempDir, "maven", "3.9.6", "bin")
+	_ = os.MkdirAll(mvnDir, 0755)
+	_, _ = os.Create(filepath.Join(mvnDir, "mvn"))
+
+	mockShell.On("Output").Return([]byte(tempDir), nil)
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Run(func(mock.Arguments) {
+		data, _ := os.ReadFile(mvnArgs[1])
+		rendered = string(data)
+	}).Return(nil)
+
+	assert.Equal(t, 0, execMvn([]string{"verify"}, mockProvider))
+	assert.Equal(t, "--settings", mvnArgs[0])
+	assert.NotEqual(t, profiles.File("test"), mvnArgs[1])
+	assert.Contains(t, rendered, "<password>s3cret</password>")
+	assert.NoFileExists(t, mvnArgs[1])
+}
+
+func TestPrintSecrets(t *testing.T) {
+	initMvnTest(t)
+
+	stdout := os.Stdout
+
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	_ = printSecrets()
+	_ = profiles.SetSecret("nexus-token", "s3cret")
+	_ = printSecrets()
+	_ = w.Close()
+
+	result, _ := io.ReadAll(r)
+	output := string(result)
+
+	os.Stdout = stdout
+
+	assert.Equal(t, "No secrets stored\n  nexus-token\n", output)
+}
@@ -0,0 +1,128 @@
+package cmd
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"io"
+	"menv/profiles"
+	"os"
+)
+
+var (
+	securityForce          bool
+	securityGenerate       bool
+	encryptPasswordProfile string
+)
+
+// securityCmd represents the security command
+var securityCmd = &cobra.Command{
+	Use:   "security",
+	Short: "Manage the master password used to encrypt the passwords of a profile",
+	Long: `Maven can decrypt encrypted passwords in the settings.xml with a master password stored in settings-security.xml.
+With this command every profile can have its own master password, which is stored in
+~/.config/menv/settings-security.xml.<profile>. When a profile, or a profile it extends, has a master password, maven
+is executed with -Dsettings.security pointing to it.
+
+Use 'menv encrypt-password' to encrypt a password for the settings.xml of the profile.`,
+}
+
+var securityInitCmd = &cobra.Command{
+	Use:               "init [profile]",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: profiles.CustomProfileCompletion,
+	Short:             "Create the master password of the provided profile, or the active profile if none is provided",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(firstArg(args))
+		if profile == "" {
+			return
+		}
+
+		err := initSecurity(profile, securityGenerate, securityForce, os.Stdin)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Created master password of profile %v in %v\n", profile, profiles.SecurityFile(profile))
+	},
+}
+
+var encryptPasswordCmd = &cobra.Command{
+	Use:   "encrypt-password [password]",
+	Args:  cobra.MaximumNArgs(1),
+	Short: "Encrypt a password with the master password of the active profile",
+	Long: `This command encrypts a password with the master password of the active profile, or the profile provided with
+--profile. The result can be used as <password> in the settings.xml of the profile. When no password is provided, it
+is asked for.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(encryptPasswordProfile)
+		if profile == "" {
+			return
+		}
+
+		password := firstArg(args)
+		if password == "" {
+			var err error
+			password, err = promptSecret(bufio.NewReader(os.Stdin), os.Stdin, "Password: ")
+			if err != nil {
+				fmt.Println(err)
+				return
+			}
+		}
+
+		encrypted, err := profiles.EncryptProfilePassword(profile, password)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Println(encrypted)
+	},
+}
+
+func initSecurity(profile string, generate bool, force bool, in io.Reader) error {
+	if !profiles.Exists(profile) {
+		return errors.New(fmt.Sprintf("profile %v does not exist", profile))
+	}
+	if profiles.SecurityExists(profile) && !force {
+		return errors.New(fmt.Sprintf("profile %v already has a master password, use --force to replace it", profile))
+	}
+
+	master := ""
+	if generate {
+		random := make([]byte, 24)
+		if _, err := rand.Read(random); err != nil {
+			return err
+		}
+		master = base64.StdEncoding.EncodeToString(random)
+	} else {
+		reader := bufio.NewReader(in)
+		var err error
+		master, err = promptSecret(reader, in, "Master password: ")
+		if err != nil {
+			return err
+		}
+		confirmation, err := promptSecret(reader, in, "Repeat master password: ")
+		if err != nil {
+			return err
+		}
+		if master == "" || master != confirmation {
+			return errors.New("the master passwords are empty or do not match")
+		}
+	}
+
+	return profiles.InitSecurity(profile, master, force)
+}
+
+func init() {
+	rootCmd.AddCommand(securityCmd)
+	securityCmd.AddCommand(securityInitCmd)
+	securityInitCmd.Flags().BoolVarP(&securityForce, "force", "f", false, "replace an existing master password")
+	securityInitCmd.Flags().BoolVar(&securityGenerate, "generate", false, "generate a random master password instead of asking for one")
+
+	rootCmd.AddCommand(encryptPasswordCmd)
+	encryptPasswordCmd.Flags().StringVarP(&encryptPasswordProfile, "profile", "p", "", "profile to use instead of the active profile")
+	_ = encryptPasswordCmd.RegisterFlagCompletionFunc("profile", profiles.CustomProfileCompletion)
+}
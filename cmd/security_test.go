@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"menv/profiles"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitSecurity(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+
+	assert.EqualError(t, initSecurity("test", false, false, strings.NewReader("one\ntwo\n")), "the master passwords are empty or do not match")
+	assert.False(t, profiles.SecurityExists("test"))
+
+	assert.NoError(t, initSecurity("test", false, false, strings.NewReader("master\nmaster\n")))
+	master, _ := profiles.MasterPassword("test")
+	assert.Equal(t, "master", master)
+
+	assert.EqualError(t, initSecurity("test", true, false, nil), "profile test already has a master password, use --force to replace it")
+	assert.NoError(t, initSecurity("test", true, true, nil))
+	master, _ = profiles.MasterPassword("test")
+	assert.Len(t, master, 32)
+
+	assert.EqualError(t, initSecurity("unknown", true, false, nil), "profile unknown does not exist")
+}
+
+func TestExecMvnSettingsSecurity(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	_ = profiles.Set("test")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	var mvnArgs []string
+	mockProvider := func(command string, args ...string) profiles.ShellCommand {
+		mvnArgs = args
+		return &mockShell
+	}
+
+	tempDir := t.TempDir()
+	mvnDir := filepath.Join(tempDir, "maven", "3.9.6", "bin")
+	_ = os.MkdirAll(mvnDir, 0755)
+	_, _ = os.Create(filepath.Join(mvnDir, "mvn"))
+
+	mockShell.On("Output").Return([]byte(tempDir), nil)
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(nil)
+
+	execMvn([]string{"verify"}, mockProvider)
+	assert.NotContains(t, strings.Join(mvnArgs, " "), "-Dsettings.security")
+
+	_ = profiles.InitSecurity("test", "master", false)
+	execMvn([]string{"verify"}, mockProvider)
+	assert.Contains(t, mvnArgs, "-Dsettings.security="+profiles.SecurityFile("test"))
+}
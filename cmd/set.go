@@ -76,6 +76,20 @@ func PromptForProfile() string {
 
 }
 
+// resolveProfile returns the given profile, or the active profile if none is given, or prompts for a profile if none
+// is active.
+func resolveProfile(profile string) string {
+	if profile == "" {
+		profile, _ = profiles.Active()
+	}
+
+	if profile == "" {
+		profile = PromptForProfile()
+	}
+
+	return profile
+}
+
 func init() {
 	rootCmd.AddCommand(setCmd)
 }
@@ -0,0 +1,219 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"menv/color"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// shimMarker identifies the shims generated by menv.
+const shimMarker = "# generated by menv"
+
+var (
+	shimDir  string
+	shimMvnd bool
+)
+
+// shimCommands maps the name of every shim to the menv command it runs.
+var shimCommands = map[string]string{
+	"mvn":  "mvn",
+	"mvnw": "mvn",
+	"mvnd": "mvnd",
+}
+
+// shimCmd represents the shim command
+var shimCmd = &cobra.Command{
+	Use:   "shim",
+	Short: "Manage the mvn shims that run maven through menv",
+	Long: `With this command you can generate shims for mvn and mvnw, and optionally mvnd, that run maven through menv with
+the active profile. The shims pass their arguments unchanged, so quoted arguments like -Dmsg="hello world" keep
+working. The directory of the shims must come before any other maven installation in PATH, which
+'menv shim doctor' checks.`,
+}
+
+var shimInstallCmd = &cobra.Command{
+	Use:   "install",
+	Args:  cobra.NoArgs,
+	Short: "Generate the shims",
+	Run: func(cmd *cobra.Command, args []string) {
+		installed, err := installShims(shimDir, shimMvnd)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		for _, file := range installed {
+			fmt.Printf("Installed %v\n", file)
+		}
+	},
+}
+
+var shimDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Args:  cobra.NoArgs,
+	Short: "Check that the shims are installed and found first in PATH",
+	Run: func(cmd *cobra.Command, args []string) {
+		problems := shimProblems(shimDir, os.Getenv("PATH"))
+		if len(problems) == 0 {
+			fmt.Println(color.Format(color.GREEN, "The shims are installed and found first in PATH"))
+			return
+		}
+		for _, problem := range problems {
+			fmt.Println(color.Format(color.RED, "! ") + problem)
+		}
+	},
+}
+
+var shimUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Args:  cobra.NoArgs,
+	Short: "Remove the shims",
+	Run: func(cmd *cobra.Command, args []string) {
+		removed, err := uninstallShims(shimDir)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		if len(removed) == 0 {
+			fmt.Printf("No shims found in %v\n", shimDir)
+		}
+		for _, file := range removed {
+			fmt.Printf("Removed %v\n", file)
+		}
+	},
+}
+
+func defaultShimDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "bin")
+}
+
+func shimScript(name string) string {
+	return "#!/bin/sh\n" + shimMarker + ", remove with 'menv shim uninstall'\nexec menv " + shimCommands[name] + " \"$@\"\n"
+}
+
+// isShim reports whether the given file is a shim generated by menv.
+func isShim(file string) bool {
+	data, err := os.ReadFile(file)
+	return err == nil && strings.Contains(string(data), shimMarker)
+}
+
+// installShims writes the mvn and mvnw shims, and with mvnd the mvnd shim, to the given directory. Files that were not
+// generated by menv are not overwritten.
+func installShims(dir string, mvnd bool) ([]string, error) {
+	names := []string{"mvn", "mvnw"}
+	if mvnd {
+		names = append(names, "mvnd")
+	}
+
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		if _, err := os.Stat(file); err == nil && !isShim(file) {
+			return nil, errors.New(fmt.Sprintf("%v already exists and was not generated by menv", file))
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	installed := make([]string, 0)
+	for _, name := range names {
+		file := filepath.Join(dir, name)
+		if err := os.WriteFile(file, []byte(shimScript(name)), 0755); err != nil {
+			return installed, err
+		}
+		// an existing shim keeps its permissions when it is written
+		if err := os.Chmod(file, 0755); err != nil {
+			return installed, err
+		}
+		installed = append(installed, file)
+	}
+	return installed, nil
+}
+
+// uninstallShims removes the shims generated by menv from the given directory.
+func uninstallShims(dir string) ([]string, error) {
+	removed := make([]string, 0)
+	for _, name := range mapKeys(shimCommands) {
+		file := filepath.Join(dir, name)
+		if !isShim(file) {
+			continue
+		}
+		if err := os.Remove(file); err != nil {
+			return removed, err
+		}
+		removed = append(removed, file)
+	}
+	return removed, nil
+}
+
+// shimProblems checks that the shims in the given directory are installed and found first in the given PATH, and
+// that they can run menv.
+func shimProblems(dir string, path string) []string {
+	problems := make([]string, 0)
+	dir = filepath.Clean(dir)
+	dirs := filepath.SplitList(path)
+	for i := range dirs {
+		if dirs[i] != "" {
+			dirs[i] = filepath.Clean(dirs[i])
+		}
+	}
+	if !slices.Contains(dirs, dir) {
+		problems = append(problems, fmt.Sprintf("%v is not in PATH", dir))
+	}
+
+	for _, name := range mapKeys(shimCommands) {
+		file := filepath.Join(dir, name)
+		if _, err := os.Stat(file); err != nil {
+			// the mvnd shim is optional
+			if name != "mvnd" {
+				problems = append(problems, fmt.Sprintf("the %v shim is not installed, run 'menv shim install'", name))
+			}
+			continue
+		}
+		if !isShim(file) {
+			problems = append(problems, fmt.Sprintf("%v was not generated by menv", file))
+			continue
+		}
+		if !isExecutable(file) {
+			problems = append(problems, fmt.Sprintf("%v is not executable", file))
+			continue
+		}
+
+		found := lookPath(name, dirs)
+		if found != "" && found != file && slices.Contains(dirs, dir) {
+			problems = append(problems, fmt.Sprintf("%v resolves to %v, move %v before %v in PATH", name, found, dir, filepath.Dir(found)))
+		}
+	}
+
+	if lookPath("menv", dirs) == "" {
+		problems = append(problems, "menv is not in PATH, the shims cannot run it")
+	}
+	return problems
+}
+
+// lookPath returns the first executable with the given name in the given directories.
+func lookPath(name string, dirs []string) string {
+	for _, dir := range dirs {
+		if dir == "" {
+			dir = "."
+		}
+		if file := filepath.Join(dir, name); isExecutable(file) {
+			return file
+		}
+	}
+	return ""
+}
+
+func init() {
+	rootCmd.AddCommand(shimCmd)
+	shimCmd.AddCommand(shimInstallCmd)
+	shimCmd.AddCommand(shimDoctorCmd)
+	shimCmd.AddCommand(shimUninstallCmd)
+	shimCmd.PersistentFlags().StringVar(&shimDir, "dir", defaultShimDir(), "directory of the shims")
+	shimInstallCmd.Flags().BoolVar(&shimMvnd, "mvnd", false, "generate a shim for the maven daemon as well")
+}
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallShims(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "bin")
+
+	installed, err := installShims(dir, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "mvn"), filepath.Join(dir, "mvnw")}, installed)
+	assert.NoFileExists(t, filepath.Join(dir, "mvnd"))
+
+	data, _ := os.ReadFile(filepath.Join(dir, "mvnw"))
+	assert.Equal(t, "#!/bin/sh\n# generated by menv, remove with 'menv shim uninstall'\nexec menv mvn \"$@\"\n", string(data))
+	assert.True(t, isExecutable(filepath.Join(dir, "mvn")))
+	assert.True(t, isMenvShim(filepath.Join(dir, "mvn")))
+
+	installed, err = installShims(dir, true)
+	assert.NoError(t, err)
+	assert.Len(t, installed, 3)
+
+	removed, err := uninstallShims(dir)
+	assert.NoError(t, err)
+	assert.Len(t, removed, 3)
+	assert.NoFileExists(t, filepath.Join(dir, "mvn"))
+}
+
+func TestInstallShimsKeepsOtherFiles(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "mvn"), []byte("#!/bin/sh\n"), 0755)
+
+	_, err := installShims(dir, false)
+	assert.EqualError(t, err, filepath.Join(dir, "mvn")+" already exists and was not generated by menv")
+
+	removed, _ := uninstallShims(dir)
+	assert.Empty(t, removed)
+	assert.FileExists(t, filepath.Join(dir, "mvn"))
+}
+
+func TestShimArguments(t *testing.T) {
+	dir := t.TempDir()
+	_, _ = installShims(dir, false)
+	// a fake menv that prints every argument on its own line
+	_ = os.WriteFile(filepath.Join(dir, "menv"), []byte("#!/bin/sh\nfor arg in \"$@\"; do echo \"$arg\"; done\n"), 0755)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	cmd := exec.Command(filepath.Join(dir, "mvn"), "-Dmsg=hello world", "*")
+	output, err := cmd.Output()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"mvn", "-Dmsg=hello world", "*"}, strings.Split(strings.TrimSpace(string(output)), "\n"))
+}
+
+func TestShimProblems(t *testing.T) {
+	dir := t.TempDir()
+	other := t.TempDir()
+	_ = os.WriteFile(filepath.Join(other, "mvn"), []byte("#!/bin/sh\n"), 0755)
+	_ = os.WriteFile(filepath.Join(other, "menv"), []byte("#!/bin/sh\n"), 0755)
+
+	assert.Equal(t, []string{
+		dir + " is not in PATH",
+		"the mvn shim is not installed, run 'menv shim install'",
+		"the mvnw shim is not installed, run 'menv shim install'",
+	}, shimProblems(dir, other))
+
+	_, _ = installShims(dir, false)
+	assert.Equal(t, []string{
+		"mvn resolves to " + filepath.Join(other, "mvn") + ", move " + dir + " before " + other + " in PATH",
+	}, shimProblems(dir, other+string(os.PathListSeparator)+dir))
+	assert.Empty(t, shimProblems(dir, dir+string(os.PathListSeparator)+other))
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/beevik/etree"
+	"github.com/spf13/cobra"
+	"menv/profiles"
+	"strconv"
+)
+
+var (
+	toolchainsProfile string
+	toolchainsForce   bool
+)
+
+// toolchainsCmd represents the toolchains command
+var toolchainsCmd = &cobra.Command{
+	Use:   "toolchains",
+	Short: "Manage the toolchains.xml of a profile",
+	Long:  `With this command you can manage the toolchains.xml of a profile. Use 'menv edittoolchains' to edit it.`,
+}
+
+var toolchainsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Args:  cobra.NoArgs,
+	Short: "Generate a toolchains.xml from the installed JDKs for the active profile, or the profile provided with --profile",
+	Long: `This command generates a toolchains.xml with a JDK toolchain for every major java version installed on this
+machine. When a major version is installed more than once, the newest installation is used.
+
+An existing toolchains.xml is only overwritten when --force is provided.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := resolveProfile(toolchainsProfile)
+		if profile == "" {
+			return
+		}
+
+		err := generateToolchains(profile, discoverJdks(), toolchainsForce)
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("Generated %v\n", profiles.ToolchainsFile(profile))
+	},
+}
+
+func generateToolchains(profile string, jdks []jdk, force bool) error {
+	if !profiles.Exists(profile) {
+		return errors.New(fmt.Sprintf("profile %v does not exist", profile))
+	}
+	if profiles.ToolchainsExists(profile) && !force {
+		return errors.New(fmt.Sprintf("profile %v already has a toolchains.xml, use --force to overwrite it", profile))
+	}
+	if len(jdks) == 0 {
+		return errors.New("no JDKs found")
+	}
+
+	content, err := toolchainsXml(jdks)
+	if err != nil {
+		return err
+	}
+	return profiles.WriteToolchains(profile, content)
+}
+
+// toolchainsXml returns a toolchains.xml with a JDK toolchain for the newest JDK of every major version. The given
+// JDKs must be sorted newest first.
+func toolchainsXml(jdks []jdk) ([]byte, error) {
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	root := doc.CreateElement("toolchains")
+	root.CreateAttr("xmlns", "http://maven.apache.org/TOOLCHAINS/1.1.0")
+	root.CreateAttr("xmlns:xsi", "http://www.w3.org/2001/XMLSchema-instance")
+	root.CreateAttr("xsi:schemaLocation", "http://maven.apache.org/TOOLCHAINS/1.1.0 http://maven.apache.org/xsd/toolchains-1.1.0.xsd")
+
+	seen := make(map[string]bool)
+	for _, j := range jdks {
+		major := majorJavaVersion(j.Version)
+		if seen[major] || j.Version == "unknown" {
+			continue
+		}
+		seen[major] = true
+
+		version := major
+		if number, err := strconv.Atoi(major); err == nil && number < 9 {
+			version = "1." + major
+		}
+
+		toolchain := root.CreateElement("toolchain")
+		toolchain.CreateElement("type").SetText("jdk")
+		provides := toolchain.CreateElement("provides")
+		provides.CreateElement("version").SetText(version)
+		if j.Vendor != "" {
+			provides.CreateElement("vendor").SetText(j.Vendor)
+		}
+		toolchain.CreateElement("configuration").CreateElement("jdkHome").SetText(j.Home)
+	}
+
+	doc.Indent(2)
+	return doc.WriteToBytes()
+}
+
+func init() {
+	rootCmd.AddCommand(toolchainsCmd)
+	toolchainsCmd.AddCommand(toolchainsGenerateCmd)
+	toolchainsGenerateCmd.Flags().StringVarP(&toolchainsProfile, "profile", "p", "", "profile to use instead of the active profile")
+	toolchainsGenerateCmd.Flags().BoolVarP(&toolchainsForce, "force", "f", false, "overwrite an existing toolchains.xml")
+	_ = toolchainsGenerateCmd.RegisterFlagCompletionFunc("profile", profiles.CustomProfileCompletion)
+}
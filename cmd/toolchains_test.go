@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+	"menv/profiles"
+	"testing"
+)
+
+func TestToolchainsXml(t *testing.T) {
+	jdks := []jdk{
+		{Version: "21.0.2", Vendor: "Eclipse Adoptium", Home: "/jvm/21"},
+		{Version: "17.0.9", Vendor: "Amazon.com Inc.", Home: "/jvm/17.0.9"},
+		{Version: "17.0.2", Home: "/jvm/17.0.2"},
+		{Version: "8.0.392", Home: "/jvm/8"},
+		{Version: "unknown", Home: "/jvm/unknown"},
+	}
+
+	content, err := toolchainsXml(jdks)
+	assert.NoError(t, err)
+
+	doc := etree.NewDocument()
+	assert.NoError(t, doc.ReadFromBytes(content))
+	toolchains := doc.FindElements("//toolchain")
+	assert.Len(t, toolchains, 3)
+
+	assert.Equal(t, "jdk", toolchains[0].FindElement("type").Text())
+	assert.Equal(t, "21", toolchains[0].FindElement("provides/version").Text())
+	assert.Equal(t, "Eclipse Adoptium", toolchains[0].FindElement("provides/vendor").Text())
+	assert.Equal(t, "/jvm/21", toolchains[0].FindElement("configuration/jdkHome").Text())
+	assert.Equal(t, "/jvm/17.0.9", toolchains[1].FindElement("configuration/jdkHome").Text())
+	assert.Equal(t, "1.8", toolchains[2].FindElement("provides/version").Text())
+	assert.Nil(t, toolchains[2].FindElement("provides/vendor"))
+}
+
+func TestGenerateToolchains(t *testing.T) {
+	initMvnTest(t)
+	_ = profiles.Create("test")
+	jdks := []jdk{{Version: "21.0.2", Home: "/jvm/21"}}
+
+	assert.EqualError(t, generateToolchains("unknown", jdks, false), "profile unknown does not exist")
+	assert.EqualError(t, generateToolchains("test", nil, false), "no JDKs found")
+
+	assert.NoError(t, generateToolchains("test", jdks, false))
+	assert.Equal(t, profiles.ToolchainsFile("test"), profiles.Toolchains("test"))
+
+	assert.EqualError(t, generateToolchains("test", jdks, false), "profile test already has a toolchains.xml, use --force to overwrite it")
+	assert.NoError(t, generateToolchains("test", jdks, true))
+}
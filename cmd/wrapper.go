@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const wrapperProperties = ".mvn/wrapper/maven-wrapper.properties"
+
+// projectRoot returns the top-level directory of the maven project the current directory belongs to. The nearest
+// directory containing a .mvn folder is the project root. Without a .mvn folder, the outermost directory of the
+// uninterrupted chain of directories containing a pom.xml is used. Outside a maven project the current directory is
+// returned.
+func projectRoot() string {
+	currentDirectory, _ := os.Getwd()
+
+	for dir := currentDirectory; ; dir = filepath.Dir(dir) {
+		if isDir(filepath.Join(dir, ".mvn")) {
+			return dir
+		}
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	root := ""
+	for dir := currentDirectory; ; dir = filepath.Dir(dir) {
+		if isFile(filepath.Join(dir, "pom.xml")) {
+			root = dir
+		} else if root != "" {
+			break
+		}
+		if dir == filepath.Dir(dir) {
+			break
+		}
+	}
+
+	if root == "" {
+		return currentDirectory
+	}
+	return root
+}
+
+// findWrapper returns the maven wrapper of the project the current directory belongs to, or an empty string if the
+// project has no wrapper.
+func findWrapper() string {
+	wrapper := filepath.Join(projectRoot(), "mvnw")
+	if !isFile(wrapper) {
+		return ""
+	}
+	return wrapper
+}
+
+// findMvnWrapper validates the given maven wrapper and returns it. The wrapper is executed from the current
+// directory, so maven builds the module the command is run in, while the wrapper itself locates the project root
+// through its .mvn folder.
+func findMvnWrapper(wrapper string) (string, error) {
+	root := filepath.Dir(wrapper)
+
+	if !isExecutable(wrapper) {
+		return "", errors.New(fmt.Sprintf("maven wrapper %v is not executable, run 'chmod +x %v'", wrapper, wrapper))
+	}
+
+	properties := filepath.Join(root, wrapperProperties)
+	file, err := os.Open(properties)
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("maven wrapper %v is broken: %v not found", wrapper, properties))
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), "=")
+		if found && strings.TrimSpace(key) == "distributionUrl" && strings.TrimSpace(value) != "" {
+			return wrapper, nil
+		}
+	}
+
+	return "", errors.New(fmt.Sprintf("maven wrapper %v is broken: %v does not contain a distributionUrl", wrapper, properties))
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func isFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
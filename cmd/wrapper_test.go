@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func createWrapper(t *testing.T, root string) string {
+	t.Helper()
+	wrapper := filepath.Join(root, "mvnw")
+	_ = os.WriteFile(wrapper, []byte("#!/bin/sh\n"), 0755)
+	_ = os.MkdirAll(filepath.Join(root, ".mvn", "wrapper"), 0755)
+	properties := "distributionUrl=https://repo.maven.apache.org/maven2/org/apache/maven/apache-maven/3.9.6/apache-maven-3.9.6-bin.zip\n"
+	_ = os.WriteFile(filepath.Join(root, wrapperProperties), []byte(properties), 0644)
+	return wrapper
+}
+
+func createModules(t *testing.T, root string, modules ...string) string {
+	t.Helper()
+	dir := root
+	_ = os.WriteFile(filepath.Join(dir, "pom.xml"), []byte("<project/>"), 0644)
+	for _, module := range modules {
+		dir = filepath.Join(dir, module)
+		_ = os.MkdirAll(dir, 0755)
+		_ = os.WriteFile(filepath.Join(dir, "pom.xml"), []byte("<project/>"), 0644)
+	}
+	return dir
+}
+
+func TestProjectRootWithMvnFolder(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	project := filepath.Join(root, "project")
+	_ = os.MkdirAll(filepath.Join(project, ".mvn"), 0755)
+	module := createModules(t, project, "parent", "module")
+	_ = os.WriteFile(filepath.Join(root, "pom.xml"), []byte("<project/>"), 0644)
+
+	_ = os.Chdir(module)
+	assert.Equal(t, project, projectRoot())
+}
+
+func TestProjectRootOutermostPom(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	project := filepath.Join(root, "project")
+	_ = os.MkdirAll(project, 0755)
+	module := createModules(t, project, "parent", "module")
+
+	_ = os.Chdir(module)
+	assert.Equal(t, project, projectRoot())
+
+	src := filepath.Join(module, "src", "main")
+	_ = os.MkdirAll(src, 0755)
+	_ = os.Chdir(src)
+	assert.Equal(t, project, projectRoot())
+}
+
+func TestProjectRootNoProject(t *testing.T) {
+	dir, _ := filepath.EvalSymlinks(t.TempDir())
+	_ = os.Chdir(dir)
+	assert.Equal(t, dir, projectRoot())
+}
+
+func TestFindWrapperFromModule(t *testing.T) {
+	root, _ := filepath.EvalSymlinks(t.TempDir())
+	expected := createWrapper(t, root)
+	module := createModules(t, root, "module")
+
+	_ = os.Chdir(module)
+	assert.Equal(t, expected, findWrapper())
+
+	wrapper, err := findMvnWrapper(findWrapper())
+	assert.NoError(t, err)
+	assert.Equal(t, expected, wrapper)
+}
+
+func TestFindWrapperNone(t *testing.T) {
+	_ = os.Chdir(t.TempDir())
+	assert.Empty(t, findWrapper())
+}
+
+func TestFindMvnWrapperBroken(t *testing.T) {
+	root := t.TempDir()
+	wrapper := createWrapper(t, root)
+	properties := filepath.Join(root, wrapperProperties)
+
+	_ = os.WriteFile(properties, []byte("wrapperVersion=3.3.2\n"), 0644)
+	_, err := findMvnWrapper(wrapper)
+	assert.EqualError(t, err, "maven wrapper "+wrapper+" is broken: "+properties+" does not contain a distributionUrl")
+
+	_ = os.Remove(properties)
+	_, err = findMvnWrapper(wrapper)
+	assert.EqualError(t, err, "maven wrapper "+wrapper+" is broken: "+properties+" not found")
+
+	_ = os.Chmod(wrapper, 0644)
+	_, err = findMvnWrapper(wrapper)
+	assert.EqualError(t, err, "maven wrapper "+wrapper+" is not executable, run 'chmod +x "+wrapper+"'")
+}
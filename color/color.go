@@ -13,6 +13,13 @@ const (
 	PURPLE
 )
 
+var enabled = true
+
+// SetEnabled turns the coloring of formatted text on or off.
+func SetEnabled(b bool) {
+	enabled = b
+}
+
 func color(c int) string {
 	if c == NONE {
 		return fmt.Sprintf("%s[%dm", escape, c)
@@ -22,5 +29,8 @@ func color(c int) string {
 }
 
 func Format(c int, text string) string {
+	if !enabled {
+		return text
+	}
 	return color(c) + text + color(NONE)
 }
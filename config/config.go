@@ -1,25 +1,65 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 )
 
+// Config holds the menv configuration. Every setting is resolved in the following order: environment variable,
+// configuration file and finally the default value.
 type Config struct {
-	MenvRoot string
-	Editor   string
-	Verbose  bool
+	MenvRoot         string   `yaml:"-"`
+	Editor           string   `yaml:"editor"`
+	Verbose          bool     `yaml:"verbose"`
+	DisableWrapper   bool     `yaml:"disable_wrapper"`
+	Discovery        []string `yaml:"discovery"`
+	DefaultProfile   string   `yaml:"default_profile"`
+	Color            bool     `yaml:"color"`
+	Exec             bool     `yaml:"exec"`
+	HistoryRetention int      `yaml:"history_retention"`
+	SecretCommand    string   `yaml:"secret_command"`
+}
+
+type setting struct {
+	env         string
+	get         func() string
+	parse       func(string) (any, error)
+	description string
 }
 
 var cfg Config
 
+// fileKeys contains the keys that are set in the configuration file.
+var fileKeys = make(map[string]bool)
+
+var settings = map[string]setting{
+	"editor":            {"MENV_EDITOR", Editor, parseString, "editor used to edit profiles"},
+	"verbose":           {"MENV_VERBOSE", func() string { return strconv.FormatBool(Verbose()) }, parseBool, "print the active profile with every mvn execution"},
+	"disable_wrapper":   {"MENV_DISABLE_WRAPPER", func() string { b, _ := DisableWrapper(); return strconv.FormatBool(b) }, parseBool, "never use the maven wrapper"},
+	"discovery":         {"MENV_DISCOVERY", func() string { return strings.Join(Discovery(), ",") }, parseList, "comma separated order in which maven installations are searched"},
+	"default_profile":   {"MENV_DEFAULT_PROFILE", DefaultProfile, parseString, "profile used when no profile is set for a folder"},
+	"color":             {"MENV_COLOR", func() string { return strconv.FormatBool(Color()) }, parseBool, "colorize output, NO_COLOR disables it"},
+	"exec":              {"MENV_EXEC", func() string { return strconv.FormatBool(Exec()) }, parseBool, "replace menv with maven instead of running it as a child process, Linux only"},
+	"history_retention": {"MENV_HISTORY_RETENTION", func() string { return strconv.Itoa(HistoryRetention()) }, parseCount, "number of snapshots kept in the history of every profile, 0 keeps all"},
+	"secret_command":    {"MENV_SECRET_COMMAND", SecretCommand, parseString, "command that resolves secrets not found in the local secrets file"},
+}
+
 func Default() Config {
 	home, _ := os.UserHomeDir()
 	return Config{
-		MenvRoot: filepath.Join(home, ".config", "menv"),
-		Editor:   "vi",
-		Verbose:  false,
+		MenvRoot:         filepath.Join(home, ".config", "menv"),
+		Editor:           "vi",
+		Verbose:          false,
+		Discovery:        []string{"homebrew", "maven_home", "path", "sdkman", "system"},
+		Color:            true,
+		HistoryRetention: 20,
 	}
 }
 
@@ -43,14 +83,257 @@ func Verbose() bool {
 	return cfg.Verbose
 }
 
+func DisableWrapper() (bool, error) {
+	env, b := os.LookupEnv("MENV_DISABLE_WRAPPER")
+	if b {
+		parsed, err := strconv.ParseBool(env)
+		if err != nil {
+			return false, errors.New("MENV_DISABLE_WRAPPER is not a boolean value")
+		}
+		return parsed, nil
+	}
+	return cfg.DisableWrapper, nil
+}
+
+// Discovery returns the order in which the maven installation resolvers are consulted.
+func Discovery() []string {
+	env, b := os.LookupEnv("MENV_DISCOVERY")
+	if b {
+		list, _ := parseList(env)
+		return list.([]string)
+	}
+	if len(cfg.Discovery) == 0 {
+		return Default().Discovery
+	}
+	return cfg.Discovery
+}
+
+// DefaultProfile returns the profile that is used when no .menv_profile file is found.
+func DefaultProfile() string {
+	profile, b := os.LookupEnv("MENV_DEFAULT_PROFILE")
+	if b {
+		return profile
+	}
+	return cfg.DefaultProfile
+}
+
+func Color() bool {
+	if _, b := os.LookupEnv("NO_COLOR"); b {
+		return false
+	}
+	env, b := os.LookupEnv("MENV_COLOR")
+	if b {
+		parseBool, err := strconv.ParseBool(env)
+		if err != nil {
+			return true
+		}
+		return parseBool
+	}
+	return cfg.Color
+}
+
+// Exec returns whether menv should replace itself with maven instead of running maven as a child process. This is
+// only supported on Linux.
+func Exec() bool {
+	env, b := os.LookupEnv("MENV_EXEC")
+	if b {
+		parseBool, err := strconv.ParseBool(env)
+		if err != nil {
+			return false
+		}
+		return parseBool
+	}
+	return cfg.Exec
+}
+
+// HistoryRetention returns the number of snapshots kept in the history of a profile, zero keeps all snapshots.
+func HistoryRetention() int {
+	env, b := os.LookupEnv("MENV_HISTORY_RETENTION")
+	if b {
+		parsed, err := parseCount(env)
+		if err != nil {
+			return cfg.HistoryRetention
+		}
+		return parsed.(int)
+	}
+	return cfg.HistoryRetention
+}
+
+// SecretCommand returns the command that resolves secret references not found in the local secrets file, like
+// "pass show {name}".
+func SecretCommand() string {
+	command, b := os.LookupEnv("MENV_SECRET_COMMAND")
+	if b {
+		return command
+	}
+	return cfg.SecretCommand
+}
+
 func Set(config Config) {
 	cfg = config
+	fileKeys = make(map[string]bool)
 }
 
 func Get() Config {
 	return cfg
 }
 
+// File returns the location of the configuration file, which can be overridden with MENV_CONFIG.
+func File() string {
+	file, b := os.LookupEnv("MENV_CONFIG")
+	if b {
+		return file
+	}
+	return filepath.Join(cfg.MenvRoot, "config.yaml")
+}
+
 func Init() error {
-	return os.MkdirAll(cfg.MenvRoot, 0755)
+	err := os.MkdirAll(cfg.MenvRoot, 0755)
+	if err != nil {
+		return err
+	}
+	return load()
+}
+
+func load() error {
+	data, err := os.ReadFile(File())
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	values := make(map[string]any)
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return errors.New(fmt.Sprintf("could not read configuration file %v: %v", File(), err))
+	}
+	for key := range values {
+		if _, ok := settings[key]; !ok {
+			return errors.New(fmt.Sprintf("unknown setting %v in configuration file %v", key, File()))
+		}
+	}
+
+	loaded := cfg
+	if err := yaml.Unmarshal(data, &loaded); err != nil {
+		return errors.New(fmt.Sprintf("could not read configuration file %v: %v", File(), err))
+	}
+
+	cfg = loaded
+	for key := range values {
+		fileKeys[key] = true
+	}
+	return nil
+}
+
+// Keys returns all known setting keys in alphabetical order.
+func Keys() []string {
+	keys := make([]string, 0, len(settings))
+	for key := range settings {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Usage describes all known settings and their environment variables, one per line in alphabetical order.
+func Usage() string {
+	var builder strings.Builder
+	for _, key := range Keys() {
+		builder.WriteString(fmt.Sprintf("  %-18v %v (%v)\n", key, settings[key].description, settings[key].env))
+	}
+	return strings.TrimSuffix(builder.String(), "\n")
+}
+
+// Value returns the effective value of the given setting.
+func Value(key string) (string, error) {
+	s, ok := settings[key]
+	if !ok {
+		return "", errors.New(fmt.Sprintf("unknown setting %v", key))
+	}
+	return s.get(), nil
+}
+
+// Source returns where the effective value of the given setting comes from: env, file or default.
+func Source(key string) string {
+	if s, ok := settings[key]; ok {
+		if _, b := os.LookupEnv(s.env); b {
+			return "env"
+		}
+	}
+	if fileKeys[key] {
+		return "file"
+	}
+	return "default"
+}
+
+// SetValue stores the given setting in the configuration file and applies it to the current configuration.
+func SetValue(key string, value string) error {
+	s, ok := settings[key]
+	if !ok {
+		return errors.New(fmt.Sprintf("unknown setting %v, valid settings are: %v", key, strings.Join(Keys(), ", ")))
+	}
+
+	parsed, err := s.parse(value)
+	if err != nil {
+		return errors.New(fmt.Sprintf("invalid value for %v: %v", key, err))
+	}
+
+	values := make(map[string]any)
+	data, err := os.ReadFile(File())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return errors.New(fmt.Sprintf("could not read configuration file %v: %v", File(), err))
+	}
+	values[key] = parsed
+
+	out, err := yaml.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	loaded := cfg
+	if err := yaml.Unmarshal(out, &loaded); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(File()), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(File(), out, 0644); err != nil {
+		return err
+	}
+
+	cfg = loaded
+	fileKeys[key] = true
+	return nil
+}
+
+func parseString(value string) (any, error) {
+	return value, nil
+}
+
+func parseBool(value string) (any, error) {
+	return strconv.ParseBool(value)
+}
+
+func parseCount(value string) (any, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return nil, errors.New("expected a number of zero or more")
+	}
+	return parsed, nil
+}
+
+func parseList(value string) (any, error) {
+	list := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" && !slices.Contains(list, item) {
+			list = append(list, item)
+		}
+	}
+	return list, nil
 }
@@ -5,14 +5,18 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 )
 
 func TestDefault(t *testing.T) {
 	home, _ := os.UserHomeDir()
 	expected := Config{
-		MenvRoot: filepath.Join(home, ".config", "menv"),
-		Editor:   "vi",
+		MenvRoot:         filepath.Join(home, ".config", "menv"),
+		Editor:           "vi",
+		Discovery:        []string{"homebrew", "maven_home", "path", "sdkman", "system"},
+		Color:            true,
+		HistoryRetention: 20,
 	}
 	actual := Default()
 
@@ -82,3 +86,123 @@ func TestInit(t *testing.T) {
 	err := Init()
 	assert.NoError(t, err)
 }
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	Set(Config{MenvRoot: dir})
+
+	assert.Equal(t, filepath.Join(dir, "config.yaml"), File())
+
+	t.Setenv("MENV_CONFIG", "/custom/config.yaml")
+	assert.Equal(t, "/custom/config.yaml", File())
+}
+
+func TestInitLoadsFile(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Default()
+	cfg.MenvRoot = dir
+	Set(cfg)
+
+	content := "editor: nano\nverbose: true\ndiscovery: [path, homebrew]\ndefault_profile: work\ncolor: false\n"
+	_ = os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0644)
+
+	err := Init()
+	assert.NoError(t, err)
+	assert.Equal(t, "nano", Editor())
+	assert.True(t, Verbose())
+	assert.Equal(t, []string{"path", "homebrew"}, Discovery())
+	assert.Equal(t, "work", DefaultProfile())
+	assert.False(t, Color())
+	assert.Equal(t, "file", Source("editor"))
+	assert.Equal(t, "default", Source("disable_wrapper"))
+
+	t.Setenv("MENV_EDITOR", "emacs")
+	assert.Equal(t, "emacs", Editor())
+	assert.Equal(t, "env", Source("editor"))
+}
+
+func TestInitInvalidFile(t *testing.T) {
+	dir := t.TempDir()
+	Set(Config{MenvRoot: dir, Editor: "vi"})
+
+	_ = os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("unknown: true\n"), 0644)
+	assert.ErrorContains(t, Init(), "unknown setting unknown")
+
+	_ = os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("verbose: [\n"), 0644)
+	assert.ErrorContains(t, Init(), "could not read configuration file")
+	assert.Equal(t, "vi", Get().Editor)
+}
+
+func TestSetValue(t *testing.T) {
+	dir := t.TempDir()
+	Set(Config{MenvRoot: dir, Editor: "vi"})
+
+	assert.NoError(t, SetValue("editor", "nano"))
+	assert.NoError(t, SetValue("discovery", "sdkman, path"))
+	assert.EqualError(t, SetValue("verbose", "maybe"), "invalid value for verbose: strconv.ParseBool: parsing \"maybe\": invalid syntax")
+	assert.ErrorContains(t, SetValue("unknown", "value"), "unknown setting unknown")
+
+	assert.Equal(t, "nano", Editor())
+	value, _ := Value("discovery")
+	assert.Equal(t, "sdkman,path", value)
+
+	Set(Config{MenvRoot: dir, Editor: "vi"})
+	assert.NoError(t, Init())
+	assert.Equal(t, "nano", Editor())
+	assert.Equal(t, []string{"sdkman", "path"}, Discovery())
+}
+
+func TestDisableWrapper(t *testing.T) {
+	Set(Config{DisableWrapper: true})
+	disabled, err := DisableWrapper()
+	assert.NoError(t, err)
+	assert.True(t, disabled)
+
+	t.Setenv("MENV_DISABLE_WRAPPER", "invalid")
+	_, err = DisableWrapper()
+	assert.EqualError(t, err, "MENV_DISABLE_WRAPPER is not a boolean value")
+}
+
+func TestColor(t *testing.T) {
+	Set(Config{Color: true})
+	assert.True(t, Color())
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, Color())
+}
+
+func TestExec(t *testing.T) {
+	Set(Config{})
+	assert.False(t, Exec())
+
+	t.Setenv("MENV_EXEC", "true")
+	assert.True(t, Exec())
+}
+
+func TestHistoryRetention(t *testing.T) {
+	Set(Config{HistoryRetention: 20})
+	assert.Equal(t, 20, HistoryRetention())
+
+	t.Setenv("MENV_HISTORY_RETENTION", "5")
+	assert.Equal(t, 5, HistoryRetention())
+
+	t.Setenv("MENV_HISTORY_RETENTION", "-1")
+	assert.Equal(t, 20, HistoryRetention())
+}
+
+func TestSecretCommand(t *testing.T) {
+	Set(Config{SecretCommand: "pass show {name}"})
+	assert.Equal(t, "pass show {name}", SecretCommand())
+
+	t.Setenv("MENV_SECRET_COMMAND", "vault read {name}")
+	assert.Equal(t, "vault read {name}", SecretCommand())
+}
+
+func TestUsage(t *testing.T) {
+	lines := strings.Split(Usage(), "\n")
+	assert.Len(t, lines, len(Keys()))
+	for i, key := range Keys() {
+		assert.True(t, strings.HasPrefix(lines[i], "  "+key+" "))
+		assert.True(t, strings.HasSuffix(lines[i], "("+settings[key].env+")"))
+	}
+}
@@ -1,14 +1,20 @@
 package main
 
 import (
+	"fmt"
 	"menv/cmd"
+	"menv/color"
 	"menv/config"
 	"menv/profiles"
+	"os"
 )
 
 func main() {
 	config.Set(config.Default())
-	_ = config.Init()
+	if err := config.Init(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	color.SetEnabled(config.Color())
 	profiles.Init(config.Get())
 	cmd.Execute()
 }
@@ -0,0 +1,340 @@
+package profiles
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"github.com/beevik/etree"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+const manifestFile = "manifest.yaml"
+
+// secretTags are the settings.xml elements that hold secrets.
+var secretTags = []string{"password", "passphrase"}
+
+// Manifest describes the contents of a profile bundle.
+type Manifest struct {
+	Version  int             `yaml:"version"`
+	Created  time.Time       `yaml:"created"`
+	Profiles []BundleProfile `yaml:"profiles"`
+}
+
+// BundleProfile describes a single profile of a bundle.
+type BundleProfile struct {
+	Name    string   `yaml:"name"`
+	Files   []string `yaml:"files"`
+	Secrets []Secret `yaml:"secrets,omitempty"`
+}
+
+// envSection is the section of the secrets redacted from the environment file of a profile.
+const envSection = "env"
+
+// Secret is a value that was redacted from the settings.xml or the environment file of a profile when it was exported.
+type Secret struct {
+	// Element is the section and id of the element holding the secret, like server/nexus, or env and the name of an
+	// environment variable, like env/TOKEN.
+	Element     string `yaml:"element"`
+	Tag         string `yaml:"tag"`
+	Placeholder string `yaml:"placeholder"`
+}
+
+// Description returns a human-readable description of the secret, like "password of server nexus".
+func (s Secret) Description() string {
+	section, id, _ := strings.Cut(s.Element, "/")
+	if section == envSection {
+		return fmt.Sprintf("%v of environment variable %v", s.Tag, id)
+	}
+	return fmt.Sprintf("%v of %v %v", s.Tag, section, id)
+}
+
+// Export writes the given profiles, including the profiles they extend, as a gzipped tar bundle with a manifest. With
+// redact the passwords and passphrases of the settings.xml files and the values of the environment files are replaced
+// by placeholders, and the master passwords of the profiles are left out.
+func Export(w io.Writer, names []string, redact bool) (Manifest, error) {
+	manifest := Manifest{Version: 1, Created: time.Now().UTC().Truncate(time.Second)}
+
+	bundled := make([]string, 0)
+	for _, name := range names {
+		chain, err := Chain(name)
+		if err != nil {
+			return manifest, err
+		}
+		for _, p := range chain {
+			if !slices.Contains(bundled, p) {
+				bundled = append(bundled, p)
+			}
+		}
+	}
+
+	contents := make(map[string][]byte)
+	for _, profile := range bundled {
+		if err := notLocked(profile); err != nil {
+			return manifest, err
+		}
+		bundleProfile := BundleProfile{Name: profile, Files: make([]string, 0)}
+		for _, file := range profileFiles {
+			data, err := os.ReadFile(file(profile))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return manifest, err
+			}
+
+			name := filepath.Base(file(profile))
+			if name == filepath.Base(SecurityFile(profile)) && redact {
+				continue
+			}
+			if name == filepath.Base(File(profile)) && redact {
+				data, bundleProfile.Secrets, err = redactSecrets(data)
+				if err != nil {
+					return manifest, errors.New(fmt.Sprintf("could not parse settings of profile %v: %v", profile, err))
+				}
+			}
+			if name == filepath.Base(EnvFile(profile)) && redact {
+				var secrets []Secret
+				data, secrets, err = redactEnv(data)
+				if err != nil {
+					return manifest, errors.New(fmt.Sprintf("could not parse environment of profile %v: %v", profile, err))
+				}
+				bundleProfile.Secrets = append(bundleProfile.Secrets, secrets...)
+			}
+			bundleProfile.Files = append(bundleProfile.Files, name)
+			contents[name] = data
+		}
+		manifest.Profiles = append(manifest.Profiles, bundleProfile)
+	}
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return manifest, err
+	}
+
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	if err := writeTarFile(tw, manifestFile, data, manifest.Created); err != nil {
+		return manifest, err
+	}
+	for _, p := range manifest.Profiles {
+		for _, name := range p.Files {
+			if err := writeTarFile(tw, name, contents[name], manifest.Created); err != nil {
+				return manifest, err
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return manifest, err
+	}
+	return manifest, gz.Close()
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: 0600, Size: int64(len(data)), ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
+
+// redactSecrets replaces the passwords and passphrases of the given settings.xml by placeholders.
+func redactSecrets(data []byte) ([]byte, []Secret, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, nil, err
+	}
+
+	secrets := make([]Secret, 0)
+	for _, tag := range secretTags {
+		for _, element := range doc.FindElements("//" + tag) {
+			if text := strings.TrimSpace(element.Text()); text == "" || strings.HasPrefix(text, SecretPrefix) {
+				continue
+			}
+
+			owner := element.Parent()
+			secret := Secret{Element: owner.Tag + "/" + elementId(owner), Tag: tag}
+			secret.Placeholder = fmt.Sprintf("MENV_REDACTED[%v/%v]", secret.Element, tag)
+			if slices.ContainsFunc(secrets, func(s Secret) bool { return s.Placeholder == secret.Placeholder }) {
+				secret.Placeholder = fmt.Sprintf("MENV_REDACTED[%v/%v#%d]", secret.Element, tag, len(secrets))
+			}
+			element.SetText(secret.Placeholder)
+			secrets = append(secrets, secret)
+		}
+	}
+
+	if len(secrets) == 0 {
+		return data, nil, nil
+	}
+	redacted, err := doc.WriteToBytes()
+	return redacted, secrets, err
+}
+
+// redactEnv replaces the values of the given environment file by placeholders. Comments are kept.
+func redactEnv(data []byte) ([]byte, []Secret, error) {
+	if _, err := ParseEnv(string(data)); err != nil {
+		return nil, nil, err
+	}
+
+	secrets := make([]Secret, 0)
+	lines := strings.Split(string(data), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		key, value, _ := strings.Cut(strings.TrimPrefix(trimmed, "export "), "=")
+		key = strings.TrimSpace(key)
+		envVar, _ := parseEnvValue(key, strings.TrimSpace(value))
+		if envVar.Value == "" {
+			continue
+		}
+
+		secret := Secret{Element: envSection + "/" + key, Tag: "value"}
+		secret.Placeholder = fmt.Sprintf("MENV_REDACTED[%v]", secret.Element)
+		if slices.ContainsFunc(secrets, func(s Secret) bool { return s.Placeholder == secret.Placeholder }) {
+			secret.Placeholder = fmt.Sprintf("MENV_REDACTED[%v#%d]", secret.Element, len(secrets))
+		}
+		lines[i] = key + "=" + secret.Placeholder
+		secrets = append(secrets, secret)
+	}
+
+	if len(secrets) == 0 {
+		return data, nil, nil
+	}
+	return []byte(strings.Join(lines, "\n")), secrets, nil
+}
+
+// ImportBundle restores the profiles of a bundle written by Export. Existing profiles are only overwritten when force
+// is true. The value of every redacted secret is requested from the given secret function.
+func ImportBundle(r io.Reader, force bool, secret func(profile string, s Secret) (string, error)) (Manifest, error) {
+	var manifest Manifest
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return manifest, errors.New(fmt.Sprintf("invalid bundle: %v", err))
+	}
+	tr := tar.NewReader(gz)
+
+	contents := make(map[string][]byte)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return manifest, errors.New(fmt.Sprintf("invalid bundle: %v", err))
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return manifest, errors.New(fmt.Sprintf("invalid bundle: %v", err))
+		}
+		contents[header.Name] = data
+	}
+
+	data, ok := contents[manifestFile]
+	if !ok {
+		return manifest, errors.New("invalid bundle: " + manifestFile + " is missing")
+	}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return manifest, errors.New(fmt.Sprintf("invalid bundle: %v", err))
+	}
+
+	for _, p := range manifest.Profiles {
+		if err := validateBundleProfile(p, contents); err != nil {
+			return manifest, err
+		}
+		if Exists(p.Name) && !force {
+			return manifest, errors.New(fmt.Sprintf("profile %v already exists, use --force to overwrite it", p.Name))
+		}
+	}
+
+	for _, p := range manifest.Profiles {
+		settings := contents[filepath.Base(File(p.Name))]
+		env := contents[filepath.Base(EnvFile(p.Name))]
+		for _, s := range p.Secrets {
+			value, err := secret(p.Name, s)
+			if err != nil {
+				return manifest, err
+			}
+			if section, key, _ := strings.Cut(s.Element, "/"); section == envSection {
+				if err := checkEnvValue(key, value); err != nil {
+					return manifest, err
+				}
+				env = []byte(strings.ReplaceAll(string(env), s.Placeholder, quoteEnvValue(value)))
+				continue
+			}
+			settings = []byte(strings.ReplaceAll(string(settings), s.Placeholder, escapeXml(value)))
+		}
+		contents[filepath.Base(File(p.Name))] = settings
+		if env != nil {
+			contents[filepath.Base(EnvFile(p.Name))] = env
+		}
+	}
+
+	for _, p := range manifest.Profiles {
+		write := func() error {
+			for _, file := range profileFiles {
+				_ = os.Remove(file(p.Name))
+			}
+			for _, name := range p.Files {
+				if err := os.WriteFile(filepath.Join(cfg.MenvRoot, name), contents[name], profileFileMode(p.Name, name)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		if Exists(p.Name) {
+			err = withSnapshot(p.Name, "import", write)
+		} else {
+			err = write()
+		}
+		if err != nil {
+			return manifest, err
+		}
+	}
+
+	return manifest, nil
+}
+
+// validateBundleProfile checks that a bundled profile has a valid name, a settings.xml and only contains files that
+// belong to it.
+func validateBundleProfile(p BundleProfile, contents map[string][]byte) error {
+	if p.Name == "" || strings.ContainsAny(p.Name, "/\\") || strings.HasPrefix(p.Name, ".") {
+		return errors.New(fmt.Sprintf("invalid bundle: invalid profile name %v", p.Name))
+	}
+
+	allowed := make([]string, 0, len(profileFiles))
+	for _, file := range profileFiles {
+		allowed = append(allowed, filepath.Base(file(p.Name)))
+	}
+
+	if !slices.Contains(p.Files, allowed[0]) {
+		return errors.New(fmt.Sprintf("invalid bundle: profile %v has no settings.xml", p.Name))
+	}
+	for _, name := range p.Files {
+		if !slices.Contains(allowed, name) {
+			return errors.New(fmt.Sprintf("invalid bundle: file %v does not belong to profile %v", name, p.Name))
+		}
+		if _, ok := contents[name]; !ok {
+			return errors.New(fmt.Sprintf("invalid bundle: file %v is missing", name))
+		}
+	}
+	return nil
+}
+
+func escapeXml(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+}
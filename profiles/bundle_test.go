@@ -0,0 +1,142 @@
+package profiles
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"testing"
+)
+
+const bundleSettings = `<settings>
+  <servers>
+    <server>
+      <id>nexus</id>
+      <username>deployer</username>
+      <password>s3cret</password>
+    </server>
+    <server>
+      <id>signing</id>
+      <passphrase>gpg-pass</passphrase>
+    </server>
+  </servers>
+</settings>
+`
+
+func TestExportImportBundle(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("team")
+	_ = os.WriteFile(File("team"), []byte(bundleSettings), 0644)
+	_ = Extend("team", "base")
+	_ = SetMvnOpts("team", "-Xmx2g")
+	_ = SetEnv("team", "TOKEN", "abc")
+
+	var bundle bytes.Buffer
+	manifest, err := Export(&bundle, []string{"team"}, true)
+	assert.NoError(t, err)
+	assert.Len(t, manifest.Profiles, 2)
+	assert.Equal(t, "team", manifest.Profiles[0].Name)
+	assert.Equal(t, []string{"settings.xml.team", "team.maven_opts", "team.extends", "team.env"}, manifest.Profiles[0].Files)
+	assert.Equal(t, "base", manifest.Profiles[1].Name)
+	assert.Len(t, manifest.Profiles[0].Secrets, 3)
+	assert.Equal(t, "password of server nexus", manifest.Profiles[0].Secrets[0].Description())
+	assert.Equal(t, "value of environment variable TOKEN", manifest.Profiles[0].Secrets[2].Description())
+	assert.NotContains(t, bundleFiles(t, bundle.Bytes())["team.env"], "abc")
+
+	initTest(t)
+	asked := make([]string, 0)
+	_, err = ImportBundle(bytes.NewReader(bundle.Bytes()), false, func(profile string, s Secret) (string, error) {
+		asked = append(asked, profile+": "+s.Description())
+		return "<" + s.Tag + ">", nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"team: password of server nexus", "team: passphrase of server signing", "team: value of environment variable TOKEN"}, asked)
+
+	assert.ElementsMatch(t, []string{"base", "team"}, Profiles())
+	assert.Equal(t, "base", Parent("team"))
+	assert.Equal(t, "-Xmx2g", MvnOpts("team"))
+	data, _ := os.ReadFile(File("team"))
+	assert.Contains(t, string(data), "<password>&lt;password&gt;</password>")
+	assert.Contains(t, string(data), "<passphrase>&lt;passphrase&gt;</passphrase>")
+	assert.NotContains(t, string(data), "MENV_REDACTED")
+	vars, _ := Env("team")
+	assert.Equal(t, []EnvVar{{Key: "TOKEN", Value: "<value>"}}, vars)
+	info, _ := os.Stat(EnvFile("team"))
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	_, err = ImportBundle(bytes.NewReader(bundle.Bytes()), false, nil)
+	assert.EqualError(t, err, "profile team already exists, use --force to overwrite it")
+}
+
+func TestExportWithoutRedact(t *testing.T) {
+	initTest(t)
+	_ = Create("team")
+	_ = os.WriteFile(File("team"), []byte(bundleSettings), 0644)
+
+	var bundle bytes.Buffer
+	_, err := Export(&bundle, []string{"team"}, false)
+	assert.NoError(t, err)
+
+	initTest(t)
+	manifest, err := ImportBundle(&bundle, false, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, manifest.Profiles[0].Secrets)
+	data, _ := os.ReadFile(File("team"))
+	assert.Equal(t, bundleSettings, string(data))
+}
+
+func TestImportBundleAbortsWithoutSecret(t *testing.T) {
+	initTest(t)
+	_ = Create("team")
+	_ = os.WriteFile(File("team"), []byte(bundleSettings), 0644)
+
+	var bundle bytes.Buffer
+	_, _ = Export(&bundle, []string{"team"}, true)
+
+	initTest(t)
+	_, err := ImportBundle(&bundle, false, func(profile string, s Secret) (string, error) {
+		return "", errors.New("aborted")
+	})
+	assert.EqualError(t, err, "aborted")
+	assert.False(t, Exists("team"))
+}
+
+func TestImportInvalidBundle(t *testing.T) {
+	initTest(t)
+
+	_, err := ImportBundle(bytes.NewReader([]byte("not a bundle")), false, nil)
+	assert.ErrorContains(t, err, "invalid bundle")
+}
+
+func TestRedactEnv(t *testing.T) {
+	data := []byte("# tokens\nexport TOKEN=abc\nEMPTY=\nURL='https://$host'\n")
+
+	redacted, secrets, err := redactEnv(data)
+	assert.NoError(t, err)
+	assert.Equal(t, "# tokens\nTOKEN=MENV_REDACTED[env/TOKEN]\nEMPTY=\nURL=MENV_REDACTED[env/URL]\n", string(redacted))
+	assert.Equal(t, []Secret{
+		{Element: "env/TOKEN", Tag: "value", Placeholder: "MENV_REDACTED[env/TOKEN]"},
+		{Element: "env/URL", Tag: "value", Placeholder: "MENV_REDACTED[env/URL]"},
+	}, secrets)
+
+	_, _, err = redactEnv([]byte("NOT VALID\n"))
+	assert.EqualError(t, err, "line 1: expected KEY=VALUE")
+}
+
+// bundleFiles returns the contents of the files of the given bundle by their name.
+func bundleFiles(t *testing.T, bundle []byte) map[string]string {
+	gz, err := gzip.NewReader(bytes.NewReader(bundle))
+	assert.NoError(t, err)
+	tr := tar.NewReader(gz)
+
+	files := make(map[string]string)
+	for header, err := tr.Next(); err == nil; header, err = tr.Next() {
+		data, _ := io.ReadAll(tr)
+		files[header.Name] = string(data)
+	}
+	return files
+}
@@ -0,0 +1,295 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"github.com/beevik/etree"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+const maskedValue = "******"
+
+// secretOptionPattern matches the names of system properties in MAVEN_OPTS that hold secrets.
+var secretOptionPattern = regexp.MustCompile(`(?i)password|secret|token`)
+
+// diffSections are the settings.xml sections compared entry by entry, in the order they are reported.
+var diffSections = []string{"servers", "mirrors", "proxies", "profiles", "activeProfiles", "pluginGroups"}
+
+// Change is a difference between two profiles. For settings it describes an entry of a section, like a server, for
+// MAVEN_OPTS a single option.
+type Change struct {
+	Section string
+	Id      string
+	Kind    string
+	// Fields describes the changed fields of a changed entry, like "username: alice -> bob".
+	Fields []string
+}
+
+// Diff compares the effective settings and MAVEN_OPTS of two profiles. Secrets are masked in the result.
+func Diff(a string, b string) ([]Change, error) {
+	settingsA, err := Merged(a)
+	if err != nil {
+		return nil, err
+	}
+	settingsB, err := Merged(b)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := DiffSettings(settingsA, settingsB)
+	if err != nil {
+		return nil, err
+	}
+	return append(changes, DiffOpts(MvnOpts(a), MvnOpts(b))...), nil
+}
+
+// DiffSettings compares two settings.xml documents. Servers, mirrors, proxies and profiles are compared by id,
+// activeProfiles and pluginGroups by value and any other element by its value.
+func DiffSettings(a []byte, b []byte) ([]Change, error) {
+	rootA, err := settingsRoot(a)
+	if err != nil {
+		return nil, err
+	}
+	rootB, err := settingsRoot(b)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]Change, 0)
+	for _, section := range diffSections {
+		if slices.Contains(keyedSections, section) {
+			changes = append(changes, diffById(section, rootA.SelectElement(section), rootB.SelectElement(section))...)
+		} else {
+			changes = append(changes, diffByValue(section, rootA.SelectElement(section), rootB.SelectElement(section))...)
+		}
+	}
+
+	fields := diffFields(flatten(rootA, diffSections), flatten(rootB, diffSections))
+	if len(fields) > 0 {
+		changes = append(changes, Change{Section: "settings", Kind: Changed, Fields: fields})
+	}
+	return changes, nil
+}
+
+func settingsRoot(data []byte) (*etree.Element, error) {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(data); err != nil {
+		return nil, errors.New(fmt.Sprintf("could not parse settings: %v", err))
+	}
+	if doc.Root() == nil {
+		return nil, errors.New("could not parse settings: no root element")
+	}
+	return doc.Root(), nil
+}
+
+func diffById(section string, a *etree.Element, b *etree.Element) []Change {
+	entriesA, idsA := entriesById(a)
+	entriesB, idsB := entriesById(b)
+
+	changes := make([]Change, 0)
+	for _, id := range idsA {
+		entryB, ok := entriesB[id]
+		if !ok {
+			changes = append(changes, Change{Section: section, Id: id, Kind: Removed})
+			continue
+		}
+		if fields := diffFields(flatten(entriesA[id], nil), flatten(entryB, nil)); len(fields) > 0 {
+			changes = append(changes, Change{Section: section, Id: id, Kind: Changed, Fields: fields})
+		}
+	}
+	for _, id := range idsB {
+		if _, ok := entriesA[id]; !ok {
+			changes = append(changes, Change{Section: section, Id: id, Kind: Added})
+		}
+	}
+	return changes
+}
+
+// entriesById returns the entries of a section by their id, and the ids in document order. Entries without an id are
+// identified by their position.
+func entriesById(section *etree.Element) (map[string]*etree.Element, []string) {
+	entries := make(map[string]*etree.Element)
+	ids := make([]string, 0)
+	if section == nil {
+		return entries, ids
+	}
+
+	for i, element := range section.ChildElements() {
+		id := elementId(element)
+		if id == "" {
+			id = fmt.Sprintf("#%d", i+1)
+		}
+		entries[id] = element
+		ids = append(ids, id)
+	}
+	return entries, ids
+}
+
+func diffByValue(section string, a *etree.Element, b *etree.Element) []Change {
+	valuesA := sectionValues(a)
+	valuesB := sectionValues(b)
+
+	changes := make([]Change, 0)
+	for _, value := range valuesA {
+		if !slices.Contains(valuesB, value) {
+			changes = append(changes, Change{Section: section, Id: value, Kind: Removed})
+		}
+	}
+	for _, value := range valuesB {
+		if !slices.Contains(valuesA, value) {
+			changes = append(changes, Change{Section: section, Id: value, Kind: Added})
+		}
+	}
+	return changes
+}
+
+func sectionValues(section *etree.Element) []string {
+	values := make([]string, 0)
+	if section == nil {
+		return values
+	}
+	for _, element := range section.ChildElements() {
+		values = append(values, strings.TrimSpace(element.Text()))
+	}
+	return values
+}
+
+// flatten returns the text of the leaf elements below the given element by their path, like
+// configuration/timeout. Repeated paths are numbered and the given top-level sections are skipped.
+func flatten(element *etree.Element, skip []string) map[string]string {
+	result := make(map[string]string)
+	var walk func(*etree.Element, string)
+	walk = func(e *etree.Element, prefix string) {
+		counts := make(map[string]int)
+		for _, child := range e.ChildElements() {
+			if prefix == "" && slices.Contains(skip, child.Tag) {
+				continue
+			}
+
+			path := prefix + child.Tag
+			counts[path]++
+			if counts[path] > 1 {
+				path = fmt.Sprintf("%v[%d]", path, counts[path])
+			}
+
+			if len(child.ChildElements()) == 0 {
+				result[path] = strings.TrimSpace(child.Text())
+			} else {
+				walk(child, path+"/")
+			}
+		}
+	}
+	walk(element, "")
+	return result
+}
+
+// diffFields describes the differences between two flattened elements, with the values of secrets masked.
+func diffFields(a map[string]string, b map[string]string) []string {
+	paths := make([]string, 0)
+	for path := range a {
+		paths = append(paths, path)
+	}
+	for path := range b {
+		if _, ok := a[path]; !ok {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	fields := make([]string, 0)
+	for _, path := range paths {
+		valueA, inA := a[path]
+		valueB, inB := b[path]
+		switch {
+		case !inA:
+			fields = append(fields, fmt.Sprintf("%v: added %v", path, maskSecret(path, valueB)))
+		case !inB:
+			fields = append(fields, fmt.Sprintf("%v: removed %v", path, maskSecret(path, valueA)))
+		case valueA != valueB:
+			fields = append(fields, fmt.Sprintf("%v: %v -> %v", path, maskSecret(path, valueA), maskSecret(path, valueB)))
+		}
+	}
+	return fields
+}
+
+func maskSecret(path string, value string) string {
+	tag := path[strings.LastIndex(path, "/")+1:]
+	tag, _, _ = strings.Cut(tag, "[")
+	if slices.Contains(secretTags, tag) && value != "" {
+		return maskedValue
+	}
+	return value
+}
+
+// DiffOpts compares two MAVEN_OPTS option by option. Options like -Xmx2g and -Dkey=value are compared by their name,
+// so a changed value is reported as a change of that option. The values of system properties named like a password,
+// secret or token are masked.
+func DiffOpts(a string, b string) []Change {
+	optionsA, namesA := optionsByName(a)
+	optionsB, namesB := optionsByName(b)
+
+	changes := make([]Change, 0)
+	for _, name := range namesA {
+		optionB, ok := optionsB[name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Section: "MAVEN_OPTS", Id: maskOption(optionsA[name]), Kind: Removed})
+		case optionB != optionsA[name]:
+			changes = append(changes, Change{Section: "MAVEN_OPTS", Id: name, Kind: Changed, Fields: []string{fmt.Sprintf("%v -> %v", maskOption(optionsA[name]), maskOption(optionB))}})
+		}
+	}
+	for _, name := range namesB {
+		if _, ok := optionsA[name]; !ok {
+			changes = append(changes, Change{Section: "MAVEN_OPTS", Id: maskOption(optionsB[name]), Kind: Added})
+		}
+	}
+	return changes
+}
+
+// maskOption masks the value of a system property named like a password, secret or token.
+func maskOption(option string) string {
+	name, value, found := strings.Cut(option, "=")
+	if !strings.HasPrefix(name, "-D") || !found || value == "" || !secretOptionPattern.MatchString(name) {
+		return option
+	}
+	return name + "=" + maskedValue
+}
+
+func optionsByName(opts string) (map[string]string, []string) {
+	options := make(map[string]string)
+	names := make([]string, 0)
+	for _, option := range strings.Fields(opts) {
+		name := optionName(option)
+		if _, ok := options[name]; !ok {
+			names = append(names, name)
+		}
+		options[name] = option
+	}
+	return options, names
+}
+
+// optionName returns the name identifying a JVM option, like -Xmx for -Xmx2g, -Dkey for -Dkey=value and
+// -XX:UseG1GC for -XX:+UseG1GC.
+func optionName(option string) string {
+	switch {
+	case strings.HasPrefix(option, "-D"):
+		name, _, _ := strings.Cut(option, "=")
+		return name
+	case strings.HasPrefix(option, "-XX:"):
+		name, _, _ := strings.Cut(option, "=")
+		return "-XX:" + strings.TrimLeft(strings.TrimPrefix(name, "-XX:"), "+-")
+	case len(option) > 4 && slices.Contains([]string{"-Xmx", "-Xms", "-Xss", "-Xmn"}, option[:4]):
+		return option[:4]
+	default:
+		return option
+	}
+}
@@ -0,0 +1,95 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+const diffSettingsA = `<settings>
+  <localRepository>/repo/a</localRepository>
+  <servers>
+    <server>
+      <id>nexus</id>
+      <username>alice</username>
+      <password>secret-a</password>
+    </server>
+    <server>
+      <id>snapshots</id>
+    </server>
+  </servers>
+  <activeProfiles>
+    <activeProfile>test</activeProfile>
+  </activeProfiles>
+</settings>
+`
+
+const diffSettingsB = `<settings>
+  <activeProfiles><activeProfile>prod</activeProfile></activeProfiles>
+  <servers>
+    <server><id>releases</id></server>
+    <server>
+      <password>secret-b</password>
+      <username>bob</username>
+      <id>nexus</id>
+    </server>
+  </servers>
+  <localRepository>/repo/b</localRepository>
+</settings>
+`
+
+func TestDiffSettings(t *testing.T) {
+	changes, err := DiffSettings([]byte(diffSettingsA), []byte(diffSettingsB))
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{
+		{Section: "servers", Id: "nexus", Kind: Changed, Fields: []string{"password: ****** -> ******", "username: alice -> bob"}},
+		{Section: "servers", Id: "snapshots", Kind: Removed},
+		{Section: "servers", Id: "releases", Kind: Added},
+		{Section: "activeProfiles", Id: "test", Kind: Removed},
+		{Section: "activeProfiles", Id: "prod", Kind: Added},
+		{Section: "settings", Kind: Changed, Fields: []string{"localRepository: /repo/a -> /repo/b"}},
+	}, changes)
+
+	changes, err = DiffSettings([]byte(diffSettingsA), []byte(diffSettingsA))
+	assert.NoError(t, err)
+	assert.Empty(t, changes)
+
+	_, err = DiffSettings([]byte("<settings>"), []byte(diffSettingsA))
+	assert.ErrorContains(t, err, "could not parse settings")
+}
+
+func TestDiffOpts(t *testing.T) {
+	changes := DiffOpts("-Xmx1g -Dfoo=bar -XX:+UseG1GC -ea", "-ea -Xmx2g -XX:-UseG1GC -Dnew=1")
+	assert.Equal(t, []Change{
+		{Section: "MAVEN_OPTS", Id: "-Xmx", Kind: Changed, Fields: []string{"-Xmx1g -> -Xmx2g"}},
+		{Section: "MAVEN_OPTS", Id: "-Dfoo=bar", Kind: Removed},
+		{Section: "MAVEN_OPTS", Id: "-XX:UseG1GC", Kind: Changed, Fields: []string{"-XX:+UseG1GC -> -XX:-UseG1GC"}},
+		{Section: "MAVEN_OPTS", Id: "-Dnew=1", Kind: Added},
+	}, changes)
+}
+
+func TestDiffOptsMasksSecrets(t *testing.T) {
+	changes := DiffOpts("-Ddb.password=old -Dtoken= -Dfoo=bar", "-Ddb.password=new -DapiSecret=s3cret -Dfoo=baz")
+	assert.Equal(t, []Change{
+		{Section: "MAVEN_OPTS", Id: "-Ddb.password", Kind: Changed, Fields: []string{"-Ddb.password=****** -> -Ddb.password=******"}},
+		{Section: "MAVEN_OPTS", Id: "-Dtoken=", Kind: Removed},
+		{Section: "MAVEN_OPTS", Id: "-Dfoo", Kind: Changed, Fields: []string{"-Dfoo=bar -> -Dfoo=baz"}},
+		{Section: "MAVEN_OPTS", Id: "-DapiSecret=******", Kind: Added},
+	}, changes)
+}
+
+func TestDiff(t *testing.T) {
+	initTest(t)
+	_ = Create("a")
+	_ = Create("b")
+	_ = os.WriteFile(File("a"), []byte(diffSettingsA), 0644)
+	_ = os.WriteFile(File("b"), []byte(diffSettingsA), 0644)
+	_ = SetMvnOpts("b", "-Xmx2g")
+
+	changes, err := Diff("a", "b")
+	assert.NoError(t, err)
+	assert.Equal(t, []Change{{Section: "MAVEN_OPTS", Id: "-Xmx2g", Kind: Added}}, changes)
+
+	_, err = Diff("a", "unknown")
+	assert.EqualError(t, err, "profile unknown does not exist")
+}
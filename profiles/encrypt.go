@@ -0,0 +1,330 @@
+package profiles
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path/filepath"
+)
+
+// encryptedHeader starts the settings.xml of an encrypted profile, which holds all files of the profile.
+const encryptedHeader = "# menv encrypted profile\n"
+
+// keyIterations is the number of PBKDF2 iterations used to derive the key of an encrypted profile.
+var keyIterations = 600000
+
+// encryptedFiles are the files of a profile that are encrypted. The file naming the profile it extends stays in clear
+// text, so the profiles extending a base profile are known without unlocking them.
+var encryptedFiles = []func(string) string{
+	File, OptsFile, MavenVersionFile, EnvFile, JavaFile, ToolchainsFile, isolatedFile, SecurityFile,
+}
+
+// unlocked maps the encrypted profiles decrypted by Unlock to the temporary directory holding their files.
+var unlocked = make(map[string]string)
+
+// encryptedProfile is the content of the settings.xml of an encrypted profile.
+type encryptedProfile struct {
+	Version    int    `yaml:"version"`
+	KeyFile    string `yaml:"key_file,omitempty"`
+	Salt       string `yaml:"salt"`
+	Iterations int    `yaml:"iterations"`
+	// Data are the files of the profile by their name, sealed with the derived key.
+	Data string `yaml:"data"`
+}
+
+// storedFile returns the settings.xml of the given profile in the menv directory, even while it is unlocked.
+func storedFile(profile string) string {
+	dir, name := storeDir(profile)
+	return dir + "/settings.xml." + name
+}
+
+// Encrypted reports whether the given profile is stored encrypted.
+func Encrypted(profile string) bool {
+	file, err := os.Open(storedFile(profile))
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	header := make([]byte, len(encryptedHeader))
+	n, _ := file.Read(header)
+	return string(header[:n]) == encryptedHeader
+}
+
+// Locked reports whether the given profile is encrypted and not unlocked, so its files cannot be read.
+func Locked(profile string) bool {
+	_, ok := unlocked[profile]
+	return !ok && Encrypted(profile)
+}
+
+// notLocked returns an error if the given profile is encrypted and not unlocked.
+func notLocked(profile string) error {
+	if Locked(profile) {
+		return errors.New(fmt.Sprintf("profile %v is encrypted", profile))
+	}
+	return nil
+}
+
+// EncryptionKeyFile returns the key file the given profile was encrypted with, or an empty string if it was encrypted
+// with a passphrase.
+func EncryptionKeyFile(profile string) string {
+	encrypted, err := readEncrypted(profile)
+	if err != nil {
+		return ""
+	}
+	return encrypted.KeyFile
+}
+
+// Encrypt encrypts the files of the given profile with the given passphrase, or the content of the given key file,
+// except for the profile it extends. The history of the profile is removed, because it holds the previous settings in
+// clear text.
+func Encrypt(profile string, passphrase []byte, keyFile string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	if Encrypted(profile) {
+		return errors.New(fmt.Sprintf("profile %v is already encrypted", profile))
+	}
+	if keyFile != "" {
+		var err error
+		keyFile, err = filepath.Abs(keyFile)
+		if err != nil {
+			return err
+		}
+		passphrase, err = os.ReadFile(keyFile)
+		if err != nil {
+			return errors.New(fmt.Sprintf("could not read key file: %v", err))
+		}
+	}
+	if len(passphrase) == 0 {
+		return errors.New("the passphrase or key file must not be empty")
+	}
+
+	files, err := readProfileFiles(profile)
+	if err != nil {
+		return err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	encrypted := encryptedProfile{
+		Version:    1,
+		KeyFile:    keyFile,
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Iterations: keyIterations,
+	}
+	if err := writeEncrypted(profile, encrypted, deriveKey(passphrase, salt, keyIterations), files); err != nil {
+		return err
+	}
+
+	for _, file := range encryptedFiles[1:] {
+		_ = os.Remove(file(profile))
+	}
+	_ = os.RemoveAll(HistoryDir(profile))
+	return nil
+}
+
+// Decrypt stores the files of the given encrypted profile in clear text again.
+func Decrypt(profile string, passphrase []byte) error {
+	if !Encrypted(profile) {
+		return errors.New(fmt.Sprintf("profile %v is not encrypted", profile))
+	}
+	if _, ok := unlocked[profile]; ok {
+		return errors.New(fmt.Sprintf("profile %v is in use", profile))
+	}
+
+	_, _, files, err := openEncrypted(profile, passphrase)
+	if err != nil {
+		return err
+	}
+	if _, ok := files[filepath.Base(File(profile))]; !ok {
+		return errors.New(fmt.Sprintf("encrypted profile %v has no settings.xml", profile))
+	}
+
+	// the settings.xml replaces the encrypted profile, so it is written last
+	dir, _ := storeDir(profile)
+	settings := filepath.Base(File(profile))
+	for name, data := range files {
+		if name == settings {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, profileFileMode(profile, name)); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, settings), files[settings], 0644); err != nil {
+		return err
+	}
+	return os.Chmod(filepath.Join(dir, settings), 0644)
+}
+
+// Unlock decrypts the files of the given encrypted profile into a temporary directory only the current user can read,
+// and uses them for the profile until the returned lock function is called. The lock function encrypts the files
+// again when they were changed, and wipes the temporary directory.
+func Unlock(profile string, passphrase []byte) (func() error, error) {
+	if _, ok := unlocked[profile]; ok {
+		return func() error { return nil }, nil
+	}
+
+	encrypted, key, files, err := openEncrypted(profile, passphrase)
+	if err != nil {
+		return func() error { return nil }, err
+	}
+
+	dir, err := os.MkdirTemp("", "menv-profile-*")
+	if err != nil {
+		return func() error { return nil }, err
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0600); err != nil {
+			wipe(dir)
+			return func() error { return nil }, err
+		}
+	}
+	unlocked[profile] = dir
+
+	return func() error {
+		defer func() {
+			delete(unlocked, profile)
+			wipe(dir)
+		}()
+
+		current, err := readProfileFiles(profile)
+		if err != nil {
+			return err
+		}
+		if sameFiles(files, current) {
+			return nil
+		}
+		return writeEncrypted(profile, encrypted, key, current)
+	}, nil
+}
+
+func readEncrypted(profile string) (encryptedProfile, error) {
+	encrypted := encryptedProfile{}
+	data, err := os.ReadFile(storedFile(profile))
+	if err != nil {
+		return encrypted, err
+	}
+	if !bytes.HasPrefix(data, []byte(encryptedHeader)) {
+		return encrypted, errors.New(fmt.Sprintf("profile %v is not encrypted", profile))
+	}
+	if err := yaml.Unmarshal(data, &encrypted); err != nil {
+		return encrypted, errors.New(fmt.Sprintf("could not read encrypted profile %v: %v", profile, err))
+	}
+	return encrypted, nil
+}
+
+// openEncrypted decrypts the files of the given profile, and returns them with the key they were encrypted with.
+func openEncrypted(profile string, passphrase []byte) (encryptedProfile, []byte, map[string][]byte, error) {
+	encrypted, err := readEncrypted(profile)
+	if err != nil {
+		return encrypted, nil, nil, err
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(encrypted.Salt)
+	if err != nil {
+		return encrypted, nil, nil, errors.New(fmt.Sprintf("could not read encrypted profile %v: %v", profile, err))
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encrypted.Data)
+	if err != nil {
+		return encrypted, nil, nil, errors.New(fmt.Sprintf("could not read encrypted profile %v: %v", profile, err))
+	}
+
+	key := deriveKey(passphrase, salt, encrypted.Iterations)
+	plain, err := openSealed(key, sealed)
+	if err != nil {
+		return encrypted, nil, nil, errors.New(fmt.Sprintf("could not decrypt profile %v, the passphrase or key file is wrong", profile))
+	}
+
+	files := make(map[string][]byte)
+	if err := yaml.Unmarshal(plain, &files); err != nil {
+		return encrypted, nil, nil, errors.New(fmt.Sprintf("could not read encrypted profile %v: %v", profile, err))
+	}
+	return encrypted, key, files, nil
+}
+
+func writeEncrypted(profile string, encrypted encryptedProfile, key []byte, files map[string][]byte) error {
+	plain, err := yaml.Marshal(files)
+	if err != nil {
+		return err
+	}
+	sealed, err := seal(key, plain)
+	if err != nil {
+		return err
+	}
+	encrypted.Data = base64.StdEncoding.EncodeToString(sealed)
+
+	data, err := yaml.Marshal(encrypted)
+	if err != nil {
+		return err
+	}
+	// the settings.xml usually exists already, so its permissions are not set by writing it
+	if err := os.WriteFile(storedFile(profile), append([]byte(encryptedHeader), data...), 0600); err != nil {
+		return err
+	}
+	return os.Chmod(storedFile(profile), 0600)
+}
+
+// readProfileFiles returns the existing files of the given profile that are encrypted, by their name.
+func readProfileFiles(profile string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, file := range encryptedFiles {
+		data, err := os.ReadFile(file(profile))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.Base(file(profile))] = data
+	}
+	return files, nil
+}
+
+func sameFiles(a map[string][]byte, b map[string][]byte) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, data := range a {
+		if other, ok := b[name]; !ok || !bytes.Equal(data, other) {
+			return false
+		}
+	}
+	return true
+}
+
+// profileFileMode returns the permissions of the given file of a profile, files that may hold secrets are only
+// readable by the current user.
+func profileFileMode(profile string, name string) os.FileMode {
+	if name == filepath.Base(EnvFile(profile)) || name == filepath.Base(SecurityFile(profile)) {
+		return 0600
+	}
+	return 0644
+}
+
+// wipe overwrites the files in the given directory with zeros before removing it.
+func wipe(dir string) {
+	entries, _ := os.ReadDir(dir)
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		_ = os.WriteFile(filepath.Join(dir, entry.Name()), make([]byte, info.Size()), 0600)
+	}
+	_ = os.RemoveAll(dir)
+}
+
+// deriveKey derives a 256-bit key from the given passphrase with PBKDF2-HMAC-SHA256.
+func deriveKey(passphrase []byte, salt []byte, iterations int) []byte {
+	return pbkdf2.Key(passphrase, salt, iterations, 32, sha256.New)
+}
@@ -0,0 +1,147 @@
+package profiles
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func initEncryptTest(t *testing.T) {
+	initTest(t)
+	iterations := keyIterations
+	keyIterations = 1000
+	t.Cleanup(func() {
+		keyIterations = iterations
+	})
+}
+
+func TestDeriveKey(t *testing.T) {
+	// test vectors of PBKDF2-HMAC-SHA256
+	assert.Equal(t, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b", hex.EncodeToString(deriveKey([]byte("password"), []byte("salt"), 1)))
+	assert.Equal(t, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43", hex.EncodeToString(deriveKey([]byte("password"), []byte("salt"), 2)))
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	initEncryptTest(t)
+	settings := "<settings><servers><server><id>nexus</id><password>s3cret</password></server></servers></settings>"
+	_ = Create("work")
+	_ = os.WriteFile(File("work"), []byte(settings), 0644)
+	_ = SetMvnOpts("work", "-Xmx2g")
+	_ = os.WriteFile(EnvFile("work"), []byte("TOKEN=abc\n"), 0600)
+	_, _ = takeSnapshot("work", "edit")
+
+	assert.False(t, Encrypted("work"))
+	assert.NoError(t, Encrypt("work", []byte("passphrase"), ""))
+	assert.True(t, Encrypted("work"))
+	assert.True(t, Locked("work"))
+	assert.True(t, Exists("work"))
+	assert.Contains(t, Profiles(), "work")
+	assert.EqualError(t, Encrypt("work", []byte("passphrase"), ""), "profile work is encrypted")
+
+	data, _ := os.ReadFile(File("work"))
+	assert.NotContains(t, string(data), "s3cret")
+	assert.NoFileExists(t, OptsFile("work"))
+	assert.NoFileExists(t, EnvFile("work"))
+	assert.NoDirExists(t, HistoryDir("work"))
+	info, _ := os.Stat(File("work"))
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	_, err := Merged("work")
+	assert.EqualError(t, err, "profile work is encrypted")
+	assert.EqualError(t, EditOpts("work", ExecCmdProvider), "profile work is encrypted")
+
+	assert.EqualError(t, Decrypt("work", []byte("wrong")), "could not decrypt profile work, the passphrase or key file is wrong")
+	assert.NoError(t, Decrypt("work", []byte("passphrase")))
+	assert.False(t, Encrypted("work"))
+
+	data, _ = os.ReadFile(File("work"))
+	assert.Equal(t, settings, string(data))
+	assert.Equal(t, "-Xmx2g", MvnOpts("work"))
+	info, _ = os.Stat(EnvFile("work"))
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	assert.EqualError(t, Decrypt("work", []byte("passphrase")), "profile work is not encrypted")
+}
+
+func TestEncryptWithKeyFile(t *testing.T) {
+	initEncryptTest(t)
+	_ = Create("work")
+	keyFile := filepath.Join(t.TempDir(), "work.key")
+	_ = os.WriteFile(keyFile, []byte("random key"), 0600)
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	assert.EqualError(t, Encrypt("work", nil, missing), "could not read key file: open "+missing+": no such file or directory")
+	assert.NoError(t, Encrypt("work", nil, keyFile))
+	assert.Equal(t, keyFile, EncryptionKeyFile("work"))
+	assert.NoError(t, Decrypt("work", []byte("random key")))
+	assert.Equal(t, "", EncryptionKeyFile("work"))
+}
+
+func TestUnlock(t *testing.T) {
+	initEncryptTest(t)
+	_ = Create("work")
+	_ = SetMvnOpts("work", "-Xmx2g")
+	_ = Encrypt("work", []byte("passphrase"), "")
+	stored, _ := os.ReadFile(File("work"))
+
+	_, err := Unlock("work", []byte("wrong"))
+	assert.EqualError(t, err, "could not decrypt profile work, the passphrase or key file is wrong")
+
+	lock, err := Unlock("work", []byte("passphrase"))
+	assert.NoError(t, err)
+	assert.False(t, Locked("work"))
+	dir := filepath.Dir(File("work"))
+	assert.NotEqual(t, cfg.MenvRoot, dir)
+	assert.Equal(t, "-Xmx2g", MvnOpts("work"))
+	_, err = Merged("work")
+	assert.NoError(t, err)
+	info, _ := os.Stat(OptsFile("work"))
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	// unchanged profiles are not encrypted again
+	assert.NoError(t, lock())
+	assert.NoDirExists(t, dir)
+	assert.True(t, Locked("work"))
+	data, _ := os.ReadFile(File("work"))
+	assert.Equal(t, stored, data)
+
+	lock, _ = Unlock("work", []byte("passphrase"))
+	assert.NoError(t, SetMvnOpts("work", "-Xmx4g"))
+	assert.NoError(t, lock())
+	assert.NoFileExists(t, OptsFile("work"))
+	assert.NoDirExists(t, HistoryDir("work"))
+
+	lock, _ = Unlock("work", []byte("passphrase"))
+	assert.Equal(t, "-Xmx4g", MvnOpts("work"))
+	assert.NoError(t, lock())
+}
+
+func TestRemoveEncrypted(t *testing.T) {
+	initEncryptTest(t)
+	_ = Create("work")
+	_ = Encrypt("work", []byte("passphrase"), "")
+
+	assert.NoError(t, Remove("work"))
+	assert.False(t, Exists("work"))
+}
+
+func TestRemoveParentOfEncrypted(t *testing.T) {
+	initEncryptTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = Extend("child", "base")
+	assert.NoError(t, Encrypt("child", []byte("passphrase"), ""))
+
+	assert.Equal(t, "base", Parent("child"))
+	assert.Equal(t, []string{"child"}, Children("base"))
+	assert.EqualError(t, Remove("base"), "profile base is extended by child")
+
+	lock, err := Unlock("child", []byte("passphrase"))
+	assert.NoError(t, err)
+	assert.Equal(t, "base", Parent("child"))
+	_, _, err = SettingsFile("child")
+	assert.NoError(t, err)
+	assert.NoError(t, lock())
+	assert.True(t, Exists("base"))
+}
@@ -0,0 +1,276 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// EnvVar is a single variable of a profile environment file.
+type EnvVar struct {
+	Key   string
+	Value string
+	// Literal is true for single quoted values, which are not expanded.
+	Literal bool
+}
+
+var envKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func EnvFile(profile string) string {
+	dir, name := profileDir(profile)
+	return dir + "/" + name + ".env"
+}
+
+func EnvExists(profile string) bool {
+	_, err := os.Stat(EnvFile(profile))
+	return !os.IsNotExist(err)
+}
+
+// Env returns the variables of the environment file of the given profile, in the order they are defined.
+func Env(profile string) ([]EnvVar, error) {
+	data, err := os.ReadFile(EnvFile(profile))
+	if os.IsNotExist(err) {
+		return []EnvVar{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	vars, err := ParseEnv(string(data))
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("%v: %v", EnvFile(profile), err))
+	}
+	return vars, nil
+}
+
+// ParseEnv parses dotenv formatted data. Empty lines and lines starting with # are ignored, an optional export
+// prefix is allowed and values can be unquoted, single quoted or double quoted.
+func ParseEnv(data string) ([]EnvVar, error) {
+	vars := make([]EnvVar, 0)
+
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\r"))
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		key, value, found := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !found || !envKeyPattern.MatchString(key) {
+			return nil, errors.New(fmt.Sprintf("line %v: expected KEY=VALUE", i+1))
+		}
+
+		envVar, err := parseEnvValue(key, strings.TrimSpace(value))
+		if err != nil {
+			return nil, errors.New(fmt.Sprintf("line %v: %v", i+1, err))
+		}
+		vars = append(vars, envVar)
+	}
+
+	return vars, nil
+}
+
+func parseEnvValue(key string, value string) (EnvVar, error) {
+	switch {
+	case strings.HasPrefix(value, "'"):
+		end := strings.Index(value[1:], "'")
+		if end < 0 {
+			return EnvVar{}, errors.New("unterminated single quoted value")
+		}
+		return EnvVar{Key: key, Value: value[1 : end+1], Literal: true}, nil
+	case strings.HasPrefix(value, `"`):
+		var builder strings.Builder
+		for i := 1; i < len(value); i++ {
+			switch value[i] {
+			case '\\':
+				if i+1 < len(value) {
+					i++
+					switch value[i] {
+					case 'n':
+						builder.WriteByte('\n')
+					case 't':
+						builder.WriteByte('\t')
+					default:
+						builder.WriteByte(value[i])
+					}
+				}
+			case '"':
+				return EnvVar{Key: key, Value: builder.String()}, nil
+			default:
+				builder.WriteByte(value[i])
+			}
+		}
+		return EnvVar{}, errors.New("unterminated double quoted value")
+	default:
+		if index := strings.Index(value, " #"); index >= 0 {
+			value = strings.TrimSpace(value[:index])
+		}
+		return EnvVar{Key: key, Value: value}, nil
+	}
+}
+
+// Environ applies the environment files of the given profile and the profiles it extends to the given environment,
+// and returns the resulting environment. ${VAR} and $VAR references are expanded using the environment built so far.
+func Environ(profile string, environ []string) ([]string, error) {
+	chain, err := Chain(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	keys := make([]string, 0)
+	for _, entry := range environ {
+		key, value, _ := strings.Cut(entry, "=")
+		if _, ok := values[key]; !ok {
+			keys = append(keys, key)
+		}
+		values[key] = value
+	}
+
+	for i := len(chain) - 1; i >= 0; i-- {
+		vars, err := Env(chain[i])
+		if err != nil {
+			return nil, err
+		}
+
+		for _, v := range vars {
+			value := v.Value
+			if !v.Literal {
+				value = os.Expand(value, func(name string) string {
+					return values[name]
+				})
+			}
+			if _, ok := values[v.Key]; !ok {
+				keys = append(keys, v.Key)
+			}
+			values[v.Key] = value
+		}
+	}
+
+	result := make([]string, 0, len(keys))
+	for _, key := range keys {
+		result = append(result, key+"="+values[key])
+	}
+	return result, nil
+}
+
+// HasEnv reports whether the given profile or one of the profiles it extends has environment variables.
+func HasEnv(profile string) bool {
+	chain, err := Chain(profile)
+	if err != nil {
+		return false
+	}
+	for _, p := range chain {
+		if EnvExists(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetEnv sets the given variable in the environment file of the given profile, replacing an existing definition.
+func SetEnv(profile string, key string, value string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	if !envKeyPattern.MatchString(key) {
+		return errors.New(fmt.Sprintf("invalid variable name %v", key))
+	}
+	if err := checkEnvValue(key, value); err != nil {
+		return err
+	}
+
+	line := key + "=" + quoteEnvValue(value)
+	lines, index, err := envLines(profile, key)
+	if err != nil {
+		return err
+	}
+
+	if index >= 0 {
+		lines[index] = line
+	} else {
+		lines = append(lines, line)
+	}
+	return writeEnvLines(profile, lines)
+}
+
+// UnsetEnv removes the given variable from the environment file of the given profile.
+func UnsetEnv(profile string, key string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+
+	lines, index, err := envLines(profile, key)
+	if err != nil {
+		return err
+	}
+	if index < 0 {
+		return errors.New(fmt.Sprintf("variable %v is not set for profile %v", key, profile))
+	}
+
+	return writeEnvLines(profile, append(lines[:index], lines[index+1:]...))
+}
+
+func EditEnv(profile string, shell func(string, ...string) ShellCommand) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	return genericEdit(profile, shell, EnvFile)
+}
+
+// envLines returns the lines of the environment file of the given profile and the index of the line defining key.
+func envLines(profile string, key string) ([]string, int, error) {
+	data, err := os.ReadFile(EnvFile(profile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, -1, err
+	}
+
+	content := strings.TrimSuffix(string(data), "\n")
+	lines := make([]string, 0)
+	if content != "" {
+		lines = strings.Split(content, "\n")
+	}
+
+	index := -1
+	for i, line := range lines {
+		trimmed := strings.TrimPrefix(strings.TrimSpace(line), "export ")
+		name, _, found := strings.Cut(trimmed, "=")
+		if found && strings.TrimSpace(name) == key {
+			index = i
+		}
+	}
+	return lines, index, nil
+}
+
+func writeEnvLines(profile string, lines []string) error {
+	content := strings.Join(lines, "\n")
+	if content != "" {
+		content += "\n"
+	}
+	return os.WriteFile(EnvFile(profile), []byte(content), 0600)
+}
+
+// checkEnvValue checks that the given value of the given variable can be quoted by quoteEnvValue. A value with $ is
+// single quoted, so it is not expanded, and a single quoted value cannot contain ' or newlines.
+func checkEnvValue(key string, value string) error {
+	if strings.Contains(value, "$") && strings.ContainsAny(value, "'\n") {
+		return errors.New(fmt.Sprintf("the value of %v cannot contain both $ and ' or a newline", key))
+	}
+	return nil
+}
+
+// quoteEnvValue quotes the given value so that it is read back unchanged. Values with $ are single quoted, because
+// $ is expanded in unquoted and double quoted values.
+func quoteEnvValue(value string) string {
+	if strings.Contains(value, "$") {
+		return "'" + value + "'"
+	}
+	if value != "" && !strings.ContainsAny(value, " \t\n\"'#\\") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
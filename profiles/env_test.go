@@ -0,0 +1,100 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestParseEnv(t *testing.T) {
+	data := `# proxy settings
+export HTTPS_PROXY=http://proxy:8080
+MAVEN_ARGS="-T 1C -Dmsg=\"hello world\""
+LITERAL='${NOT_EXPANDED}'
+PLAIN=value # trailing comment
+
+EMPTY=
+`
+	vars, err := ParseEnv(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []EnvVar{
+		{Key: "HTTPS_PROXY", Value: "http://proxy:8080"},
+		{Key: "MAVEN_ARGS", Value: `-T 1C -Dmsg="hello world"`},
+		{Key: "LITERAL", Value: "${NOT_EXPANDED}", Literal: true},
+		{Key: "PLAIN", Value: "value"},
+		{Key: "EMPTY", Value: ""},
+	}, vars)
+}
+
+func TestParseEnvInvalid(t *testing.T) {
+	_, err := ParseEnv("VALID=1\nnot a variable\n")
+	assert.EqualError(t, err, "line 2: expected KEY=VALUE")
+
+	_, err = ParseEnv("QUOTED=\"unterminated\n")
+	assert.EqualError(t, err, "line 1: unterminated double quoted value")
+}
+
+func TestEnviron(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = Extend("child", "base")
+	_ = os.WriteFile(EnvFile("base"), []byte("JAVA_HOME=/jdk/17\nTOKEN=base\n"), 0600)
+	_ = os.WriteFile(EnvFile("child"), []byte("TOKEN=child\nPATH=${JAVA_HOME}/bin:$PATH\nRAW='$PATH'\n"), 0600)
+
+	assert.True(t, HasEnv("child"))
+	environ, err := Environ("child", []string{"PATH=/usr/bin", "HOME=/home/user"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"PATH=/jdk/17/bin:/usr/bin",
+		"HOME=/home/user",
+		"JAVA_HOME=/jdk/17",
+		"TOKEN=child",
+		"RAW=$PATH",
+	}, environ)
+}
+
+func TestSetUnsetEnv(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+	assert.False(t, HasEnv("test"))
+
+	assert.NoError(t, SetEnv("test", "TOKEN", "secret"))
+	assert.NoError(t, SetEnv("test", "MAVEN_ARGS", "-T 1C"))
+	assert.NoError(t, SetEnv("test", "TOKEN", "changed"))
+
+	data, _ := os.ReadFile(EnvFile("test"))
+	assert.Equal(t, "TOKEN=changed\nMAVEN_ARGS=\"-T 1C\"\n", string(data))
+
+	vars, _ := Env("test")
+	assert.Equal(t, []EnvVar{{Key: "TOKEN", Value: "changed"}, {Key: "MAVEN_ARGS", Value: "-T 1C"}}, vars)
+
+	assert.NoError(t, UnsetEnv("test", "TOKEN"))
+	assert.EqualError(t, UnsetEnv("test", "TOKEN"), "variable TOKEN is not set for profile test")
+	assert.EqualError(t, SetEnv("test", "NOT VALID", "x"), "invalid variable name NOT VALID")
+	assert.EqualError(t, SetEnv("unknown", "TOKEN", "x"), "profile unknown does not exist")
+
+	info, _ := os.Stat(EnvFile("test"))
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+}
+
+func TestSetEnvRoundTrip(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+
+	values := []string{"abc$def", "${HOME}", "a \"quoted\" # value", "it's", "tab\tand\nnewline", "back\\slash", ""}
+	for _, value := range values {
+		assert.NoError(t, SetEnv("test", "VALUE", value))
+		environ, err := Environ("test", []string{"def=expanded", "HOME=/home/user"})
+		assert.NoError(t, err)
+		assert.Contains(t, environ, "VALUE="+value)
+	}
+
+	data, _ := os.ReadFile(EnvFile("test"))
+	assert.Equal(t, "VALUE=\"\"\n", string(data))
+	assert.NoError(t, SetEnv("test", "TOKEN", "abc$def"))
+	data, _ = os.ReadFile(EnvFile("test"))
+	assert.Equal(t, "VALUE=\"\"\nTOKEN='abc$def'\n", string(data))
+
+	assert.EqualError(t, SetEnv("test", "TOKEN", "it's $5"), "the value of TOKEN cannot contain both $ and ' or a newline")
+}
@@ -0,0 +1,229 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"github.com/beevik/etree"
+	"os"
+	"slices"
+	"strings"
+)
+
+// keyedSections are the settings.xml sections whose children are merged by their <id>.
+var keyedSections = []string{"servers", "mirrors", "proxies", "profiles"}
+
+// listSections are the settings.xml sections whose children are merged by their text value.
+var listSections = []string{"activeProfiles", "pluginGroups"}
+
+// ParentFile returns the file naming the profile the given profile extends. It is never encrypted, so it is read from
+// the menv directory even while an encrypted profile is unlocked.
+func ParentFile(profile string) string {
+	dir, name := storeDir(profile)
+	return dir + "/" + name + ".extends"
+}
+
+// Parent returns the profile the given profile extends, or an empty string if it extends none. A profile of a remote
+// that extends a profile without a namespace extends a profile of the same remote.
+func Parent(profile string) string {
+	data, err := os.ReadFile(ParentFile(profile))
+	if err != nil {
+		return ""
+	}
+
+	parent := removeNewLineFromString(string(data))
+	if remote := Remote(profile); remote != "" && parent != "" && Remote(parent) == "" {
+		return remote + "/" + parent
+	}
+	return parent
+}
+
+// Extend records parent as the base profile of profile.
+func Extend(profile string, parent string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	if !Exists(parent) {
+		return errors.New(fmt.Sprintf("profile %v does not exist", parent))
+	}
+
+	chain, err := Chain(parent)
+	if err != nil {
+		return err
+	}
+	for _, p := range chain {
+		if p == profile {
+			return errors.New(fmt.Sprintf("profile %v cannot extend %v, because it would create a cycle", profile, parent))
+		}
+	}
+
+	return os.WriteFile(ParentFile(profile), []byte(parent+"\n"), 0644)
+}
+
+// Chain returns the inheritance chain of the given profile, starting with the profile itself and ending with its
+// top-most base profile.
+func Chain(profile string) ([]string, error) {
+	chain := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for current := profile; current != ""; current = Parent(current) {
+		if seen[current] {
+			return nil, errors.New(fmt.Sprintf("profile %v has a cyclic parent chain", profile))
+		}
+		if !Exists(current) {
+			return nil, errors.New(fmt.Sprintf("profile %v does not exist", current))
+		}
+		seen[current] = true
+		chain = append(chain, current)
+	}
+
+	return chain, nil
+}
+
+// Children returns the profiles that directly extend the given profile.
+func Children(profile string) []string {
+	result := make([]string, 0)
+	for _, p := range Profiles() {
+		if Parent(p) == profile {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Merged returns the settings.xml of the given profile merged with the settings of all the profiles it extends.
+// Servers, mirrors, proxies and profiles are merged by id, activeProfiles and pluginGroups are combined and any other
+// element of a child replaces the one of its parent.
+func Merged(profile string) ([]byte, error) {
+	chain, err := Chain(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *etree.Document
+	for i := len(chain) - 1; i >= 0; i-- {
+		if err := notLocked(chain[i]); err != nil {
+			return nil, err
+		}
+		doc := etree.NewDocument()
+		if err := doc.ReadFromFile(File(chain[i])); err != nil {
+			return nil, errors.New(fmt.Sprintf("could not parse settings of profile %v: %v", chain[i], err))
+		}
+		if doc.Root() == nil {
+			return nil, errors.New(fmt.Sprintf("settings of profile %v have no root element", chain[i]))
+		}
+
+		if result == nil {
+			result = doc
+			continue
+		}
+		mergeSettings(result.Root(), doc.Root())
+	}
+
+	result.Indent(2)
+	return result.WriteToBytes()
+}
+
+func mergeSettings(base *etree.Element, child *etree.Element) {
+	for _, element := range child.ChildElements() {
+		existing := base.SelectElement(element.Tag)
+
+		switch {
+		case existing != nil && slices.Contains(keyedSections, element.Tag):
+			mergeById(existing, element)
+		case existing != nil && slices.Contains(listSections, element.Tag):
+			mergeByValue(existing, element)
+		case existing != nil:
+			base.InsertChildAt(existing.Index(), element.Copy())
+			base.RemoveChild(existing)
+		default:
+			base.AddChild(element.Copy())
+		}
+	}
+}
+
+func mergeById(base *etree.Element, child *etree.Element) {
+	for _, element := range child.ChildElements() {
+		id := elementId(element)
+		replaced := false
+
+		for _, existing := range base.ChildElements() {
+			if existing.Tag == element.Tag && id != "" && elementId(existing) == id {
+				base.InsertChildAt(existing.Index(), element.Copy())
+				base.RemoveChild(existing)
+				replaced = true
+				break
+			}
+		}
+
+		if !replaced {
+			base.AddChild(element.Copy())
+		}
+	}
+}
+
+func mergeByValue(base *etree.Element, child *etree.Element) {
+	for _, element := range child.ChildElements() {
+		found := false
+		for _, existing := range base.ChildElements() {
+			if strings.TrimSpace(existing.Text()) == strings.TrimSpace(element.Text()) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			base.AddChild(element.Copy())
+		}
+	}
+}
+
+func elementId(element *etree.Element) string {
+	id := element.SelectElement("id")
+	if id == nil {
+		return ""
+	}
+	return strings.TrimSpace(id.Text())
+}
+
+// SettingsFile returns the settings file maven should use for the given profile. For a profile that extends another
+// profile this is a temporary file with the merged settings, which is removed by calling the returned cleanup function.
+func SettingsFile(profile string) (string, func(), error) {
+	if err := notLocked(profile); err != nil {
+		return "", func() {}, err
+	}
+	if Parent(profile) == "" {
+		return File(profile), func() {}, nil
+	}
+
+	merged, err := Merged(profile)
+	if err != nil {
+		return "", func() {}, err
+	}
+
+	return temporarySettings(merged)
+}
+
+// temporarySettings writes the given settings to a temporary file readable only by the current user, which is removed
+// by calling the returned cleanup function.
+func temporarySettings(data []byte) (string, func(), error) {
+	file, err := os.CreateTemp("", "menv-settings-*.xml")
+	if err != nil {
+		return "", func() {}, err
+	}
+	defer file.Close()
+
+	cleanup := func() {
+		_ = os.Remove(file.Name())
+	}
+
+	if err := file.Chmod(0600); err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+	if _, err := file.Write(data); err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+
+	return file.Name(), cleanup, nil
+}
@@ -0,0 +1,163 @@
+package profiles
+
+import (
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"strings"
+	"testing"
+)
+
+const baseSettings = `<?xml version="1.0" encoding="UTF-8"?>
+<settings>
+  <localRepository>/base/repository</localRepository>
+  <servers>
+    <server><id>nexus</id><username>base</username></server>
+    <server><id>shared</id><username>base</username></server>
+  </servers>
+  <mirrors>
+    <mirror><id>central</id><url>https://base/central</url></mirror>
+  </mirrors>
+  <activeProfiles>
+    <activeProfile>base</activeProfile>
+  </activeProfiles>
+</settings>
+`
+
+const childSettings = `<?xml version="1.0" encoding="UTF-8"?>
+<settings>
+  <localRepository>/child/repository</localRepository>
+  <servers>
+    <server><id>nexus</id><username>child</username></server>
+    <server><id>extra</id><username>child</username></server>
+  </servers>
+  <activeProfiles>
+    <activeProfile>base</activeProfile>
+    <activeProfile>child</activeProfile>
+  </activeProfiles>
+</settings>
+`
+
+func TestExtend(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+
+	err := Extend("child", "base")
+	assert.NoError(t, err)
+	assert.Equal(t, "base", Parent("child"))
+	assert.Empty(t, Parent("base"))
+}
+
+func TestExtendNonExistent(t *testing.T) {
+	initTest(t)
+	_ = Create("child")
+
+	assert.EqualError(t, Extend("child", "base"), "profile base does not exist")
+	assert.EqualError(t, Extend("other", "child"), "profile other does not exist")
+}
+
+func TestExtendCycle(t *testing.T) {
+	initTest(t)
+	_ = Create("a")
+	_ = Create("b")
+	_ = Extend("b", "a")
+
+	assert.EqualError(t, Extend("a", "b"), "profile a cannot extend b, because it would create a cycle")
+	assert.EqualError(t, Extend("a", "a"), "profile a cannot extend a, because it would create a cycle")
+}
+
+func TestChain(t *testing.T) {
+	initTest(t)
+	_ = Create("a")
+	_ = Create("b")
+	_ = Create("c")
+	_ = Extend("b", "a")
+	_ = Extend("c", "b")
+
+	chain, err := Chain("c")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c", "b", "a"}, chain)
+}
+
+func TestChainMissingParent(t *testing.T) {
+	initTest(t)
+	_ = Create("child")
+	_ = os.WriteFile(ParentFile("child"), []byte("gone\n"), 0644)
+
+	_, err := Chain("child")
+	assert.EqualError(t, err, "profile gone does not exist")
+}
+
+func TestChildren(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = Create("other")
+	_ = Extend("child", "base")
+
+	assert.Equal(t, []string{"child"}, Children("base"))
+	assert.Empty(t, Children("child"))
+}
+
+func TestRemoveExtended(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = Extend("child", "base")
+
+	assert.EqualError(t, Remove("base"), "profile base is extended by child")
+	assert.True(t, Exists("base"))
+
+	assert.NoError(t, Remove("child"))
+	assert.NoFileExists(t, ParentFile("child"))
+	assert.NoError(t, Remove("base"))
+}
+
+func TestMerged(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = os.WriteFile(File("base"), []byte(baseSettings), 0644)
+	_ = os.WriteFile(File("child"), []byte(childSettings), 0644)
+	_ = Extend("child", "base")
+
+	merged, err := Merged("child")
+	assert.NoError(t, err)
+
+	doc := etree.NewDocument()
+	assert.NoError(t, doc.ReadFromBytes(merged))
+
+	assert.Equal(t, "/child/repository", doc.FindElement("//localRepository").Text())
+	assert.Equal(t, "child", doc.FindElement("//server[id='nexus']/username").Text())
+	assert.Equal(t, "base", doc.FindElement("//server[id='shared']/username").Text())
+	assert.Equal(t, "child", doc.FindElement("//server[id='extra']/username").Text())
+	assert.Len(t, doc.FindElements("//servers/server"), 3)
+	assert.Equal(t, "https://base/central", doc.FindElement("//mirror[id='central']/url").Text())
+	assert.Len(t, doc.FindElements("//activeProfiles/activeProfile"), 2)
+}
+
+func TestSettingsFile(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = os.WriteFile(File("base"), []byte(baseSettings), 0644)
+	_ = os.WriteFile(File("child"), []byte(childSettings), 0644)
+
+	file, cleanup, err := SettingsFile("base")
+	assert.NoError(t, err)
+	assert.Equal(t, File("base"), file)
+	cleanup()
+	assert.FileExists(t, File("base"))
+
+	_ = Extend("child", "base")
+	file, cleanup, err = SettingsFile("child")
+	assert.NoError(t, err)
+	assert.NotEqual(t, File("child"), file)
+
+	data, _ := os.ReadFile(file)
+	assert.True(t, strings.Contains(string(data), "<id>shared</id>"))
+
+	cleanup()
+	assert.NoFileExists(t, file)
+}
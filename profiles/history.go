@@ -0,0 +1,274 @@
+package profiles
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"menv/config"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const snapshotMetadata = "snapshot.yaml"
+
+// historyFiles are the files of a profile that are recorded in its history, by the name they have in a snapshot.
+var historyFiles = []struct {
+	name string
+	file func(string) string
+}{
+	{"settings.xml", File},
+	{"maven_opts", OptsFile},
+}
+
+// Snapshot is a recorded state of the settings.xml and MAVEN_OPTS of a profile.
+type Snapshot struct {
+	Rev     int       `yaml:"-"`
+	Created time.Time `yaml:"created"`
+	// Reason is the action that replaced this state, like edit, editopts, import or rollback.
+	Reason string `yaml:"reason"`
+}
+
+// FileChange summarizes the changes made to a file of a profile after a snapshot.
+type FileChange struct {
+	Name    string
+	Added   int
+	Removed int
+}
+
+func HistoryDir(profile string) string {
+	return filepath.Join(cfg.MenvRoot, "history", profile)
+}
+
+func snapshotDir(profile string, rev int) string {
+	return filepath.Join(HistoryDir(profile), strconv.Itoa(rev))
+}
+
+// History returns the snapshots of the given profile, oldest first.
+func History(profile string) ([]Snapshot, error) {
+	if !Exists(profile) {
+		return nil, errors.New(fmt.Sprintf("profile %v does not exist", profile))
+	}
+
+	dir, _ := os.ReadDir(HistoryDir(profile))
+	result := make([]Snapshot, 0)
+	for _, entry := range dir {
+		rev, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(snapshotDir(profile, rev), snapshotMetadata))
+		if err != nil {
+			continue
+		}
+		snapshot := Snapshot{}
+		if err := yaml.Unmarshal(data, &snapshot); err != nil {
+			continue
+		}
+		snapshot.Rev = rev
+		result = append(result, snapshot)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Rev < result[j].Rev
+	})
+	return result, nil
+}
+
+// takeSnapshot records the current state of the given profile in its history.
+func takeSnapshot(profile string, reason string) (Snapshot, error) {
+	history, err := History(profile)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	snapshot := Snapshot{Rev: 1, Created: time.Now().Truncate(time.Second), Reason: reason}
+	if len(history) > 0 {
+		snapshot.Rev = history[len(history)-1].Rev + 1
+	}
+
+	dir := snapshotDir(profile, snapshot.Rev)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return Snapshot{}, err
+	}
+	for _, f := range historyFiles {
+		data, err := os.ReadFile(f.file(profile))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return Snapshot{}, err
+		}
+		if err := os.WriteFile(filepath.Join(dir, f.name), data, 0600); err != nil {
+			return Snapshot{}, err
+		}
+	}
+
+	metadata, err := yaml.Marshal(snapshot)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, snapshotMetadata), metadata, 0600); err != nil {
+		return Snapshot{}, err
+	}
+	return snapshot, nil
+}
+
+// pruneHistory removes the oldest snapshots of the given profile exceeding the configured retention.
+func pruneHistory(profile string) {
+	history, _ := History(profile)
+	retention := config.HistoryRetention()
+	if retention <= 0 || len(history) <= retention {
+		return
+	}
+	for _, snapshot := range history[:len(history)-retention] {
+		_ = os.RemoveAll(snapshotDir(profile, snapshot.Rev))
+	}
+}
+
+// withSnapshot records the state of the given profile before running change, and discards the snapshot again when
+// change did not modify the profile. Encrypted profiles have no history, because it would hold their files in clear
+// text.
+func withSnapshot(profile string, reason string, change func() error) error {
+	if Encrypted(profile) {
+		return change()
+	}
+
+	snapshot, err := takeSnapshot(profile, reason)
+	if err != nil {
+		return err
+	}
+
+	err = change()
+	if changes, diffErr := snapshotChanges(profile, snapshot.Rev, ""); diffErr == nil && len(changes) == 0 {
+		_ = os.RemoveAll(snapshotDir(profile, snapshot.Rev))
+	}
+	pruneHistory(profile)
+	return err
+}
+
+// Changes summarizes the changes made to the given profile after the given snapshot, until the next snapshot or the
+// current state of the profile.
+func Changes(profile string, rev int) ([]FileChange, error) {
+	history, err := History(profile)
+	if err != nil {
+		return nil, err
+	}
+
+	next := ""
+	for i, snapshot := range history {
+		if snapshot.Rev == rev && i+1 < len(history) {
+			next = snapshotDir(profile, history[i+1].Rev)
+		}
+	}
+	return snapshotChanges(profile, rev, next)
+}
+
+// snapshotChanges compares the given snapshot with the snapshot in next, or the current state of the profile when
+// next is empty.
+func snapshotChanges(profile string, rev int, next string) ([]FileChange, error) {
+	dir := snapshotDir(profile, rev)
+	if _, err := os.Stat(filepath.Join(dir, snapshotMetadata)); err != nil {
+		return nil, errors.New(fmt.Sprintf("revision %v of profile %v does not exist", rev, profile))
+	}
+
+	changes := make([]FileChange, 0)
+	for _, f := range historyFiles {
+		before, _ := os.ReadFile(filepath.Join(dir, f.name))
+		after := []byte{}
+		if next == "" {
+			after, _ = os.ReadFile(f.file(profile))
+		} else {
+			after, _ = os.ReadFile(filepath.Join(next, f.name))
+		}
+		if bytes.Equal(before, after) {
+			continue
+		}
+
+		added, removed := diffLines(splitLines(before), splitLines(after))
+		changes = append(changes, FileChange{Name: f.name, Added: added, Removed: removed})
+	}
+	return changes, nil
+}
+
+// Rollback restores the given snapshot of the profile, or the latest snapshot when rev is zero. The state before the
+// rollback is recorded as a new snapshot, so a rollback can be undone as well.
+func Rollback(profile string, rev int) (Snapshot, error) {
+	if err := writable(profile); err != nil {
+		return Snapshot{}, err
+	}
+
+	history, err := History(profile)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if len(history) == 0 {
+		return Snapshot{}, errors.New(fmt.Sprintf("profile %v has no history", profile))
+	}
+
+	target := history[len(history)-1]
+	if rev != 0 {
+		found := false
+		for _, snapshot := range history {
+			if snapshot.Rev == rev {
+				target, found = snapshot, true
+			}
+		}
+		if !found {
+			return Snapshot{}, errors.New(fmt.Sprintf("revision %v of profile %v does not exist", rev, profile))
+		}
+	}
+
+	dir := snapshotDir(profile, target.Rev)
+	err = withSnapshot(profile, "rollback", func() error {
+		for _, f := range historyFiles {
+			data, err := os.ReadFile(filepath.Join(dir, f.name))
+			if os.IsNotExist(err) {
+				_ = os.Remove(f.file(profile))
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(f.file(profile), data, 0644); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return target, err
+}
+
+func splitLines(data []byte) []string {
+	content := strings.TrimSuffix(string(data), "\n")
+	if content == "" {
+		return []string{}
+	}
+	return strings.Split(content, "\n")
+}
+
+// diffLines returns the number of lines added and removed between before and after, based on their longest common
+// subsequence.
+func diffLines(before []string, after []string) (added int, removed int) {
+	lengths := make([][]int, len(before)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	common := lengths[0][0]
+	return len(after) - common, len(before) - common
+}
@@ -0,0 +1,117 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"menv/config"
+	"os"
+	"testing"
+)
+
+// editingProvider returns a shell provider whose editor writes the given content to the edited file.
+func editingProvider(file string, content string) func(string, ...string) ShellCommand {
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Run(func(mock.Arguments) {
+		_ = os.WriteFile(file, []byte(content), 0644)
+	}).Return(nil)
+
+	return func(string, ...string) ShellCommand {
+		return &mockShell
+	}
+}
+
+func TestHistory(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+	original, _ := os.ReadFile(File("test"))
+
+	history, err := History("test")
+	assert.NoError(t, err)
+	assert.Empty(t, history)
+
+	assert.NoError(t, Edit("test", editingProvider(File("test"), "<settings>\n</settings>\n"), nil))
+	assert.NoError(t, EditOpts("test", editingProvider(OptsFile("test"), "-Xmx2g\n")))
+
+	history, _ = History("test")
+	assert.Len(t, history, 2)
+	assert.Equal(t, 1, history[0].Rev)
+	assert.Equal(t, "edit", history[0].Reason)
+	assert.Equal(t, "editopts", history[1].Reason)
+
+	changes, err := Changes("test", 1)
+	assert.NoError(t, err)
+	assert.Len(t, changes, 1)
+	assert.Equal(t, "settings.xml", changes[0].Name)
+	assert.Equal(t, 1, changes[0].Added)
+
+	changes, _ = Changes("test", 2)
+	assert.Equal(t, []FileChange{{Name: "maven_opts", Added: 1, Removed: 0}}, changes)
+
+	snapshot, err := Rollback("test", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, snapshot.Rev)
+	data, _ := os.ReadFile(File("test"))
+	assert.Equal(t, original, data)
+	assert.False(t, MvnOptsExists("test"))
+
+	history, _ = History("test")
+	assert.Len(t, history, 3)
+	assert.Equal(t, "rollback", history[2].Reason)
+
+	_, err = Rollback("test", 42)
+	assert.EqualError(t, err, "revision 42 of profile test does not exist")
+}
+
+func TestHistorySkipsUnchangedEdits(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+	original, _ := os.ReadFile(File("test"))
+
+	assert.NoError(t, Edit("test", editingProvider(File("test"), string(original)), nil))
+
+	history, _ := History("test")
+	assert.Empty(t, history)
+	_, err := Rollback("test", 0)
+	assert.EqualError(t, err, "profile test has no history")
+}
+
+func TestHistoryRetention(t *testing.T) {
+	initTest(t)
+	config.Set(config.Config{MenvRoot: config.Get().MenvRoot, HistoryRetention: 2})
+	_ = Create("test")
+
+	for _, content := range []string{"-Xmx1g", "-Xmx2g", "-Xmx3g", "-Xmx4g"} {
+		_ = EditOpts("test", editingProvider(OptsFile("test"), content))
+	}
+
+	history, _ := History("test")
+	assert.Len(t, history, 2)
+	assert.Equal(t, 3, history[0].Rev)
+	assert.Equal(t, 4, history[1].Rev)
+}
+
+func TestImportRecordsHistory(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+
+	assert.NoError(t, Import("test", []byte("<settings/>"), "-Xmx2g", true))
+	assert.Equal(t, "-Xmx2g", MvnOpts("test"))
+
+	history, _ := History("test")
+	assert.Len(t, history, 1)
+	assert.Equal(t, "import", history[0].Reason)
+
+	assert.NoError(t, Remove("test"))
+	assert.NoDirExists(t, HistoryDir("test"))
+}
+
+func TestDiffLines(t *testing.T) {
+	added, removed := diffLines([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+	assert.Equal(t, 2, added)
+	assert.Equal(t, 1, removed)
+}
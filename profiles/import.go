@@ -0,0 +1,47 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Import creates the given profile from an existing settings.xml and, unless opts is empty, its MAVEN_OPTS. An
+// existing profile is only overwritten when force is true, all its files are replaced and its previous state is
+// recorded in its history.
+func Import(profile string, data []byte, opts string, force bool) error {
+	if err := notRemote(profile); err != nil {
+		return err
+	}
+	if Exists(profile) && !force {
+		return errors.New(fmt.Sprintf("profile %v already exists, use --force to overwrite it", profile))
+	}
+	if err := ValidateSettings(data); err != nil {
+		return err
+	}
+
+	write := func() error {
+		for _, file := range profileFiles {
+			_ = os.Remove(file(profile))
+		}
+		if err := os.WriteFile(File(profile), data, 0644); err != nil {
+			return err
+		}
+		if opts == "" {
+			return nil
+		}
+		return os.WriteFile(OptsFile(profile), []byte(opts+"\n"), 0644)
+	}
+	if !Exists(profile) {
+		return write()
+	}
+	return withSnapshot(profile, "import", write)
+}
+
+// SetMvnOpts writes the MAVEN_OPTS of the given profile.
+func SetMvnOpts(profile string, opts string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	return os.WriteFile(OptsFile(profile), []byte(opts+"\n"), 0644)
+}
@@ -0,0 +1,59 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"testing"
+)
+
+func TestImport(t *testing.T) {
+	initTest(t)
+	settings := []byte("<settings>\n  <localRepository>/tmp/repo</localRepository>\n</settings>\n")
+
+	assert.NoError(t, Import("imported", settings, "", false))
+	data, _ := os.ReadFile(File("imported"))
+	assert.Equal(t, settings, data)
+
+	assert.EqualError(t, Import("imported", settings, "", false), "profile imported already exists, use --force to overwrite it")
+	assert.NoError(t, Import("imported", []byte("<settings/>"), "", true))
+	data, _ = os.ReadFile(File("imported"))
+	assert.Equal(t, "<settings/>", string(data))
+}
+
+func TestImportForceReplacesFiles(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("imported")
+	_ = Extend("imported", "base")
+	_ = SetMvnOpts("imported", "-Xmx2g")
+	_ = SetEnv("imported", "TOKEN", "abc")
+	_ = SetJava("imported", "17")
+
+	assert.NoError(t, Import("imported", []byte("<settings/>"), "", true))
+	assert.Empty(t, Parent("imported"))
+	assert.Empty(t, Children("base"))
+	assert.False(t, MvnOptsExists("imported"))
+	assert.False(t, EnvExists("imported"))
+	assert.NoFileExists(t, JavaFile("imported"))
+
+	assert.NoError(t, Import("imported", []byte("<settings/>"), "-Xmx1g", true))
+	assert.Equal(t, "-Xmx1g", MvnOpts("imported"))
+}
+
+func TestImportInvalidSettings(t *testing.T) {
+	initTest(t)
+
+	assert.ErrorContains(t, Import("broken", []byte("<settings><servers></settings>"), "", false), "invalid settings.xml")
+	assert.EqualError(t, Import("broken", []byte("<project/>"), "", false), "invalid settings.xml: line 1, column 1: the root element must be <settings>")
+	assert.EqualError(t, Import("broken", []byte(""), "", false), "invalid settings.xml: line 1, column 1: the root element must be <settings>")
+	assert.False(t, Exists("broken"))
+}
+
+func TestSetMvnOpts(t *testing.T) {
+	initTest(t)
+	_ = Create("profile")
+
+	assert.NoError(t, SetMvnOpts("profile", "-Xmx2g"))
+	assert.Equal(t, "-Xmx2g", MvnOpts("profile"))
+	assert.EqualError(t, SetMvnOpts("unknown", "-Xmx2g"), "profile unknown does not exist")
+}
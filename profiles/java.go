@@ -0,0 +1,44 @@
+package profiles
+
+import (
+	"os"
+)
+
+func JavaFile(profile string) string {
+	dir, name := profileDir(profile)
+	return dir + "/" + name + ".java"
+}
+
+// Java returns the JDK bound to the given profile, or to the nearest profile it extends. The JDK is either a version,
+// like 17 or 21.0.2, or the path of a JDK home.
+func Java(profile string) string {
+	chain, err := Chain(profile)
+	if err != nil {
+		return ""
+	}
+
+	for _, p := range chain {
+		data, err := os.ReadFile(JavaFile(p))
+		if err == nil {
+			return removeNewLineFromString(string(data))
+		}
+	}
+	return ""
+}
+
+// SetJava binds the given JDK version or JDK home to the given profile.
+func SetJava(profile string, java string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	return os.WriteFile(JavaFile(profile), []byte(java+"\n"), 0644)
+}
+
+// ClearJava removes the JDK bound to the given profile.
+func ClearJava(profile string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	_ = os.Remove(JavaFile(profile))
+	return nil
+}
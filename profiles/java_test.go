@@ -0,0 +1,24 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestJava(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = Extend("child", "base")
+
+	assert.Empty(t, Java("child"))
+	assert.NoError(t, SetJava("base", "17"))
+	assert.Equal(t, "17", Java("child"))
+	assert.NoError(t, SetJava("child", "21"))
+	assert.Equal(t, "21", Java("child"))
+	assert.NoError(t, ClearJava("child"))
+	assert.Equal(t, "17", Java("child"))
+
+	assert.EqualError(t, SetJava("unknown", "17"), "profile unknown does not exist")
+	assert.EqualError(t, ClearJava("unknown"), "profile unknown does not exist")
+}
@@ -0,0 +1,88 @@
+package profiles
+
+import (
+	"os"
+	"path/filepath"
+)
+
+const mavenVersionFile string = ".menv_maven"
+
+func MavenVersionFile(profile string) string {
+	dir, name := profileDir(profile)
+	return dir + "/" + name + ".maven_version"
+}
+
+// DaemonDir returns the directory the maven daemons started for the given profile keep their registry and logs in.
+func DaemonDir(profile string) string {
+	return filepath.Join(cfg.MenvRoot, "mvnd", profile)
+}
+
+// MavenVersion returns the maven version pinned for the given profile, or an empty string if none is pinned.
+func MavenVersion(profile string) string {
+	data, err := os.ReadFile(MavenVersionFile(profile))
+	if err != nil {
+		return ""
+	}
+	return removeNewLineFromString(string(data))
+}
+
+// SetMavenVersion pins the given maven version for the given profile.
+func SetMavenVersion(profile string, version string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	return os.WriteFile(MavenVersionFile(profile), []byte(version+"\n"), 0644)
+}
+
+// ClearMavenVersion removes the maven version pinned for the given profile.
+func ClearMavenVersion(profile string) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	_ = os.Remove(MavenVersionFile(profile))
+	return nil
+}
+
+// PinMavenVersion pins the given maven version for the current directory and its children.
+func PinMavenVersion(version string) error {
+	return os.WriteFile(mavenVersionFile, []byte(version+"\n"), 0644)
+}
+
+// UnpinMavenVersion removes the maven version pinned for the current directory.
+func UnpinMavenVersion() {
+	_ = os.Remove(mavenVersionFile)
+}
+
+// PinnedMavenVersion returns the maven version that should be used for the current directory and where it is pinned.
+// A version pinned in a directory takes precedence over a version pinned for the given profile.
+func PinnedMavenVersion(profile string) (version string, source string) {
+	if path := findUp(mavenVersionFile); path != "" {
+		return extractActiveVersionFromFile(path), path
+	}
+
+	if profile != "" {
+		if version := MavenVersion(profile); version != "" {
+			return version, MavenVersionFile(profile)
+		}
+	}
+
+	return "", ""
+}
+
+// findUp returns the path of the given file in the current directory or the nearest parent directory containing it.
+func findUp(file string) string {
+	currentDirectory, _ := os.Getwd()
+
+	for {
+		path := filepath.Join(currentDirectory, file)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+
+		parent := filepath.Dir(currentDirectory)
+		if parent == currentDirectory {
+			return ""
+		}
+		currentDirectory = parent
+	}
+}
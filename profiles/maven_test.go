@@ -0,0 +1,47 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetMavenVersion(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+
+	assert.Empty(t, MavenVersion("test"))
+	assert.NoError(t, SetMavenVersion("test", "3.9.6"))
+	assert.Equal(t, "3.9.6", MavenVersion("test"))
+	assert.NoError(t, ClearMavenVersion("test"))
+	assert.Empty(t, MavenVersion("test"))
+
+	assert.EqualError(t, SetMavenVersion("unknown", "3.9.6"), "profile unknown does not exist")
+	assert.EqualError(t, ClearMavenVersion("unknown"), "profile unknown does not exist")
+}
+
+func TestPinnedMavenVersion(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+	_ = SetMavenVersion("test", "3.8.8")
+
+	version, source := PinnedMavenVersion("test")
+	assert.Equal(t, "3.8.8", version)
+	assert.Equal(t, MavenVersionFile("test"), source)
+
+	parent, _ := os.Getwd()
+	_ = PinMavenVersion("3.9.6")
+	child := filepath.Join(parent, "module")
+	_ = os.Mkdir(child, 0755)
+	_ = os.Chdir(child)
+
+	version, source = PinnedMavenVersion("test")
+	assert.Equal(t, "3.9.6", version)
+	assert.Equal(t, filepath.Join(parent, mavenVersionFile), source)
+
+	_ = os.Chdir(parent)
+	UnpinMavenVersion()
+	version, _ = PinnedMavenVersion("")
+	assert.Empty(t, version)
+}
@@ -0,0 +1,18 @@
+//go:build !linux && !darwin
+
+package profiles
+
+import (
+	"os"
+	"os/exec"
+)
+
+// startProcessGroup starts the given command. Process groups are only used on Linux and macOS.
+func startProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
+
+// signalProcessGroup sends the given signal to the given command.
+func signalProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	return cmd.Process.Signal(sig)
+}
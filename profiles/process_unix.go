@@ -0,0 +1,39 @@
+//go:build linux || darwin
+
+package profiles
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// startProcessGroup starts the given command. Without a controlling terminal, like in a CI job, the command gets a
+// process group of its own, so signals can be forwarded to maven and the processes it forks. With a controlling
+// terminal the command stays in the process group of menv, so the terminal delivers Ctrl-C to all of them and job
+// control like Ctrl-Z keeps working.
+func startProcessGroup(cmd *exec.Cmd) error {
+	if !hasControllingTerminal() {
+		cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	}
+	return cmd.Start()
+}
+
+// hasControllingTerminal reports whether menv has a controlling terminal.
+func hasControllingTerminal() bool {
+	tty, err := os.Open("/dev/tty")
+	if err != nil {
+		return false
+	}
+	_ = tty.Close()
+	return true
+}
+
+// signalProcessGroup sends the given signal to the process group of the given command, if it has one of its own, or
+// to the command otherwise.
+func signalProcessGroup(cmd *exec.Cmd, sig os.Signal) error {
+	if s, ok := sig.(syscall.Signal); ok && cmd.SysProcAttr != nil && cmd.SysProcAttr.Setpgid {
+		return syscall.Kill(-cmd.Process.Pid, s)
+	}
+	return cmd.Process.Signal(sig)
+}
@@ -0,0 +1,64 @@
+//go:build linux || darwin
+
+package profiles
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestExitCode(t *testing.T) {
+	assert.Equal(t, 0, ExitCode(nil))
+	assert.Equal(t, 1, ExitCode(errors.New("could not start")))
+	assert.Equal(t, 3, ExitCode(ExecCmdProvider("sh", "-c", "exit 3").Run()))
+	assert.Equal(t, 128+int(syscall.SIGTERM), ExitCode(ExecCmdProvider("sh", "-c", "kill -TERM $$").Run()))
+}
+
+func TestRunForwardsSignals(t *testing.T) {
+	cmd := ExecCmdProvider("sh", "-c", "trap 'exit 7' TERM; while true; do sleep 0.1; done")
+	result := make(chan error)
+	go func() {
+		result <- cmd.Run()
+	}()
+
+	time.Sleep(500 * time.Millisecond)
+	_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+
+	select {
+	case err := <-result:
+		assert.Equal(t, 7, ExitCode(err))
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal was not forwarded to the command")
+	}
+}
+
+func TestRunForwardsSignalsToProcessGroup(t *testing.T) {
+	if hasControllingTerminal() {
+		t.Skip("with a controlling terminal the command shares the process group of menv")
+	}
+	file := filepath.Join(t.TempDir(), "forwarded")
+	// the forked subshell only receives the signal when it is sent to the process group
+	cmd := ExecCmdProvider("sh", "-c", "(trap 'echo forwarded > "+file+"; exit 0' TERM; while true; do sleep 0.1; done) & wait")
+	result := make(chan error)
+	go func() {
+		result <- cmd.Run()
+	}()
+
+	time.Sleep(500 * time.Millisecond)
+	_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+
+	select {
+	case <-result:
+	case <-time.After(5 * time.Second):
+		t.Fatal("signal was not forwarded to the command")
+	}
+	assert.Eventually(t, func() bool {
+		_, err := os.Stat(file)
+		return err == nil
+	}, 5*time.Second, 100*time.Millisecond)
+}
@@ -8,8 +8,10 @@ import (
 	"menv/config"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"syscall"
 )
 
 const (
@@ -29,6 +31,7 @@ type ShellCommand interface {
 	Stdin(io.Reader)
 	Stdout(io.Writer)
 	Stderr(io.Writer)
+	Env([]string)
 	Output() ([]byte, error)
 }
 
@@ -36,8 +39,38 @@ type execShellCommand struct {
 	*exec.Cmd
 }
 
+// Run starts the command and waits for it to finish. While the command runs, SIGINT and SIGTERM received by menv are
+// forwarded to it, or to its process group when it has one, so menv only exits after the command and the processes it
+// forked have handled the signal. An error starting the command is printed to its stderr.
 func (e execShellCommand) Run() error {
-	return e.Cmd.Run()
+	if err := startProcessGroup(e.Cmd); err != nil {
+		stderr := e.Cmd.Stderr
+		if stderr == nil {
+			stderr = os.Stderr
+		}
+		_, _ = fmt.Fprintf(stderr, "menv: %v\n", err)
+		return err
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		for {
+			select {
+			case sig := <-signals:
+				_ = signalProcessGroup(e.Cmd, sig)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return e.Cmd.Wait()
 }
 
 func (e execShellCommand) Stdin(stdin io.Reader) {
@@ -52,17 +85,23 @@ func (e execShellCommand) Stderr(stderr io.Writer) {
 	e.Cmd.Stderr = stderr
 }
 
+func (e execShellCommand) Env(env []string) {
+	e.Cmd.Env = env
+}
+
 func (e execShellCommand) Output() ([]byte, error) {
 	return e.Cmd.Output()
 }
 
 func Create(profile string) error {
+	if err := notRemote(profile); err != nil {
+		return err
+	}
 	if Exists(profile) {
 		return errors.New(fmt.Sprintf("profile %v already exists", profile))
 	}
 
-	path := cfg.MenvRoot + "/settings.xml." + profile
-	_ = os.WriteFile(path, []byte(template), 0644)
+	_ = os.WriteFile(File(profile), []byte(template), 0644)
 	return nil
 }
 
@@ -75,6 +114,9 @@ func Profiles() []string {
 			result = append(result, strings.ReplaceAll(file.Name(), "settings.xml.", ""))
 		}
 	}
+	for _, remote := range Remotes() {
+		result = append(result, remoteProfiles(remote)...)
+	}
 	return result
 }
 
@@ -82,13 +124,28 @@ func Clear() {
 	_ = os.Remove(profileFile)
 }
 
+// profileFiles are the files that make up a profile, starting with its settings.xml.
+var profileFiles = []func(string) string{
+	File, OptsFile, ParentFile, MavenVersionFile, EnvFile, JavaFile, ToolchainsFile, isolatedFile, SecurityFile,
+}
+
 func Remove(profile string) error {
+	// an encrypted profile can be removed without unlocking it
 	if !Exists(profile) {
 		return errors.New(fmt.Sprintf("profile %v does not exist", profile))
 	}
+	if err := notRemote(profile); err != nil {
+		return err
+	}
+
+	if children := Children(profile); len(children) > 0 {
+		return errors.New(fmt.Sprintf("profile %v is extended by %v", profile, strings.Join(children, ", ")))
+	}
 
-	_ = os.Remove(cfg.MenvRoot + "/settings.xml." + profile)
-	_ = os.Remove(cfg.MenvRoot + "/" + profile + ".maven_opts")
+	for _, file := range profileFiles {
+		_ = os.Remove(file(profile))
+	}
+	_ = os.RemoveAll(HistoryDir(profile))
 	return nil
 }
 
@@ -104,7 +161,7 @@ func Set(profile string) error {
 }
 
 func Exists(profile string) bool {
-	_, err := os.Stat(cfg.MenvRoot + "/settings.xml." + profile)
+	_, err := os.Stat(File(profile))
 	return !os.IsNotExist(err)
 }
 
@@ -122,7 +179,7 @@ func Active() (profile string, path string) {
 		}
 
 		if currentDirectory == "/" {
-			return "", ""
+			return defaultProfile()
 		}
 
 		currentDirectory = filepath.Clean(filepath.Join(currentDirectory, ".."))
@@ -130,6 +187,15 @@ func Active() (profile string, path string) {
 
 }
 
+// defaultProfile returns the configured default profile and the configuration file it is set by, if any.
+func defaultProfile() (profile string, path string) {
+	profile = config.DefaultProfile()
+	if profile == "" {
+		return "", ""
+	}
+	return profile, config.File()
+}
+
 func extractActiveVersionFromFile(filePath string) (version string) {
 	fileContent, _ := os.ReadFile(filePath)
 	version = string(fileContent)
@@ -155,22 +221,59 @@ func genericEdit(profile string, shell func(string, ...string) ShellCommand, fil
 	cmd.Stdin(os.Stdin)
 	cmd.Stdout(os.Stdout)
 	cmd.Stderr(os.Stderr)
-	_ = cmd.Run()
+	if err := cmd.Run(); err != nil {
+		return errors.New(fmt.Sprintf("editor %v failed: %v", editor, err))
+	}
 	return nil
 }
 
-func Edit(profile string, shell func(string, ...string) ShellCommand) error {
-	if !Exists(profile) {
-		return errors.New(fmt.Sprintf("profile %v does not exist", profile))
+// Edit opens the settings.xml of the given profile in the editor and validates it afterwards. When the settings are
+// invalid, invalid is called with the problems found to decide whether to reopen the editor, keep the file or revert
+// it to its content before editing. Without invalid the file is kept.
+func Edit(profile string, shell func(string, ...string) ShellCommand, invalid func([]ValidationError) EditAction) error {
+	if err := writable(profile); err != nil {
+		return err
 	}
-	return genericEdit(profile, shell, File)
+
+	original, err := os.ReadFile(File(profile))
+	if err != nil {
+		return err
+	}
+
+	return withSnapshot(profile, "edit", func() error {
+		for {
+			if err := genericEdit(profile, shell, File); err != nil {
+				return err
+			}
+
+			data, err := os.ReadFile(File(profile))
+			if err != nil {
+				return err
+			}
+			problems := CheckSettings(data)
+			if len(problems) == 0 || invalid == nil {
+				return nil
+			}
+
+			switch invalid(problems) {
+			case EditReopen:
+				continue
+			case EditRevert:
+				return os.WriteFile(File(profile), original, 0644)
+			default:
+				return nil
+			}
+		}
+	})
 }
 
 func EditOpts(profile string, shell func(string, ...string) ShellCommand) error {
-	if !Exists(profile) {
-		return errors.New(fmt.Sprintf("profile %v does not exist", profile))
+	if err := writable(profile); err != nil {
+		return err
 	}
-	return genericEdit(profile, shell, OptsFile)
+	return withSnapshot(profile, "editopts", func() error {
+		return genericEdit(profile, shell, OptsFile)
+	})
 }
 
 func MvnOptsExists(profile string) bool {
@@ -186,11 +289,36 @@ func MvnOpts(profile string) string {
 	return opts
 }
 func File(profile string) string {
-	return cfg.MenvRoot + "/settings.xml." + profile
+	dir, name := profileDir(profile)
+	return dir + "/settings.xml." + name
 }
 
 func OptsFile(profile string) string {
-	return cfg.MenvRoot + "/" + profile + ".maven_opts"
+	dir, name := profileDir(profile)
+	return dir + "/" + name + ".maven_opts"
+}
+
+// ExitCode returns the exit status of a command that finished with the given error. A command killed by a signal
+// results in 128 plus the signal number, like a shell reports it.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+			return 128 + int(status.Signal())
+		}
+		return exitErr.ExitCode()
+	}
+
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode()
+	}
+
+	return 1
 }
 
 func CustomProfileCompletion(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
@@ -1,6 +1,7 @@
 package profiles
 
 import (
+	"bytes"
 	"errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -32,6 +33,10 @@ func (m MockShellCommand) Stderr(stderr io.Writer) {
 	m.Called(stderr)
 }
 
+func (m MockShellCommand) Env(env []string) {
+	m.Called(env)
+}
+
 func (m MockShellCommand) Output() ([]byte, error) {
 	return nil, nil
 }
@@ -226,14 +231,14 @@ func TestEdit(t *testing.T) {
 	mockShell.On("Stdout", os.Stdout).Return()
 	mockShell.On("Stderr", os.Stderr).Return()
 	mockShell.On("Run").Return(nil)
-	_ = Edit("test", mockProvider)
+	_ = Edit("test", mockProvider, nil)
 	mockShell.AssertExpectations(t)
 
 }
 
 func TestEditNonExistent(t *testing.T) {
 	initTest(t)
-	actual := Edit("non_existent", ExecCmdProvider)
+	actual := Edit("non_existent", ExecCmdProvider, nil)
 	expected := errors.New("profile non_existent does not exist")
 
 	assert.Error(t, actual)
@@ -320,3 +325,28 @@ func initTest(t *testing.T) {
 	Init(testConfig)
 	_ = os.Chdir(t.TempDir())
 }
+
+func TestActiveDefaultProfile(t *testing.T) {
+	initTest(t)
+	_ = Create("default")
+	t.Setenv("MENV_DEFAULT_PROFILE", "default")
+
+	profile, path := Active()
+	assert.Equal(t, "default", profile)
+	assert.Equal(t, config.File(), path)
+
+	_ = Create("test")
+	_ = Set("test")
+	profile, _ = Active()
+	assert.Equal(t, "test", profile)
+}
+
+func TestRunPrintsStartError(t *testing.T) {
+	var stderr bytes.Buffer
+	cmd := ExecCmdProvider(filepath.Join(t.TempDir(), "missing"))
+	cmd.Stderr(&stderr)
+
+	err := cmd.Run()
+	assert.Error(t, err)
+	assert.Equal(t, "menv: "+err.Error()+"\n", stderr.String())
+}
@@ -0,0 +1,204 @@
+package profiles
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// RemoteStatus describes how a remote profile store relates to its upstream git repository.
+type RemoteStatus struct {
+	Name   string
+	Url    string
+	Behind int
+	Ahead  int
+}
+
+func RemotesDir() string {
+	return filepath.Join(cfg.MenvRoot, "remotes")
+}
+
+func RemoteDir(remote string) string {
+	return filepath.Join(RemotesDir(), remote)
+}
+
+// Remote returns the remote that manages the given profile, or an empty string for a local profile. Profiles of a
+// remote are namespaced as <remote>/<profile>.
+func Remote(profile string) string {
+	remote, _, found := strings.Cut(profile, "/")
+	if !found {
+		return ""
+	}
+	return remote
+}
+
+// profileDir returns the directory holding the files of the given profile and the name of the profile within it. The
+// files of an unlocked encrypted profile are held in a temporary directory.
+func profileDir(profile string) (dir string, name string) {
+	if dir, ok := unlocked[profile]; ok {
+		return dir, profile
+	}
+	return storeDir(profile)
+}
+
+// storeDir returns the directory the files of the given profile are stored in and the name of the profile within it.
+func storeDir(profile string) (dir string, name string) {
+	remote, name, found := strings.Cut(profile, "/")
+	if !found {
+		return cfg.MenvRoot, profile
+	}
+	return RemoteDir(remote), name
+}
+
+// writable returns an error if the given profile does not exist, is managed by a remote or is encrypted and not
+// unlocked.
+func writable(profile string) error {
+	if !Exists(profile) {
+		return errors.New(fmt.Sprintf("profile %v does not exist", profile))
+	}
+	if err := notRemote(profile); err != nil {
+		return err
+	}
+	return notLocked(profile)
+}
+
+func notRemote(profile string) error {
+	if remote := Remote(profile); remote != "" {
+		return errors.New(fmt.Sprintf("profile %v is read-only, it is managed by remote %v", profile, remote))
+	}
+	return nil
+}
+
+// Remotes returns the names of the remote profile stores.
+func Remotes() []string {
+	dir, _ := os.ReadDir(RemotesDir())
+
+	result := make([]string, 0)
+	for _, entry := range dir {
+		if entry.IsDir() && isDir(filepath.Join(RemotesDir(), entry.Name(), ".git")) {
+			result = append(result, entry.Name())
+		}
+	}
+	return result
+}
+
+func remoteProfiles(remote string) []string {
+	dir, _ := os.ReadDir(RemoteDir(remote))
+
+	result := make([]string, 0)
+	for _, file := range dir {
+		if !file.IsDir() && strings.HasPrefix(file.Name(), "settings.xml.") {
+			result = append(result, remote+"/"+strings.TrimPrefix(file.Name(), "settings.xml."))
+		}
+	}
+	return result
+}
+
+// AddRemote clones the git repository at the given url as a remote profile store.
+func AddRemote(remote string, url string, shell func(string, ...string) ShellCommand) error {
+	if remote == "" || strings.ContainsAny(remote, "/\\") || strings.HasPrefix(remote, ".") {
+		return errors.New(fmt.Sprintf("invalid remote name %v", remote))
+	}
+	if _, err := os.Stat(RemoteDir(remote)); !os.IsNotExist(err) {
+		return errors.New(fmt.Sprintf("remote %v already exists", remote))
+	}
+	if err := os.MkdirAll(RemotesDir(), 0755); err != nil {
+		return err
+	}
+
+	if err := git(shell, "", "clone", "--quiet", url, RemoteDir(remote)); err != nil {
+		_ = os.RemoveAll(RemoteDir(remote))
+		return errors.New(fmt.Sprintf("could not clone %v: %v", url, err))
+	}
+	return nil
+}
+
+// PullRemote fetches the latest profiles of the given remote.
+func PullRemote(remote string, shell func(string, ...string) ShellCommand) error {
+	if err := remoteExists(remote); err != nil {
+		return err
+	}
+	if err := git(shell, RemoteDir(remote), "pull", "--quiet", "--ff-only"); err != nil {
+		return errors.New(fmt.Sprintf("could not pull remote %v: %v", remote, err))
+	}
+	return nil
+}
+
+// RemoveRemote removes the given remote and its profiles.
+func RemoveRemote(remote string) error {
+	if err := remoteExists(remote); err != nil {
+		return err
+	}
+	return os.RemoveAll(RemoteDir(remote))
+}
+
+// Status fetches the given remote and reports how many commits the local copy is behind or ahead of its upstream.
+func Status(remote string, shell func(string, ...string) ShellCommand) (RemoteStatus, error) {
+	status := RemoteStatus{Name: remote}
+	if err := remoteExists(remote); err != nil {
+		return status, err
+	}
+
+	dir := RemoteDir(remote)
+	url, err := gitOutput(shell, dir, "remote", "get-url", "origin")
+	if err != nil {
+		return status, errors.New(fmt.Sprintf("could not read url of remote %v: %v", remote, err))
+	}
+	status.Url = url
+
+	if err := git(shell, dir, "fetch", "--quiet"); err != nil {
+		return status, errors.New(fmt.Sprintf("could not fetch remote %v: %v", remote, err))
+	}
+
+	counts, err := gitOutput(shell, dir, "rev-list", "--left-right", "--count", "@{upstream}...HEAD")
+	if err != nil {
+		return status, errors.New(fmt.Sprintf("could not compare remote %v: %v", remote, err))
+	}
+	fields := strings.Fields(counts)
+	if len(fields) != 2 {
+		return status, errors.New(fmt.Sprintf("could not compare remote %v: unexpected output %v", remote, counts))
+	}
+	status.Behind, _ = strconv.Atoi(fields[0])
+	status.Ahead, _ = strconv.Atoi(fields[1])
+	return status, nil
+}
+
+func remoteExists(remote string) error {
+	for _, r := range Remotes() {
+		if r == remote {
+			return nil
+		}
+	}
+	return errors.New(fmt.Sprintf("remote %v does not exist", remote))
+}
+
+func git(shell func(string, ...string) ShellCommand, dir string, args ...string) error {
+	_, err := gitOutput(shell, dir, args...)
+	return err
+}
+
+func gitOutput(shell func(string, ...string) ShellCommand, dir string, args ...string) (string, error) {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+
+	cmd := shell("git", args...)
+	var stderr strings.Builder
+	cmd.Stderr(&stderr)
+	out, err := cmd.Output()
+	if err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", errors.New(message)
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
@@ -0,0 +1,112 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// createBareRemote creates a bare git repository with the profiles base and child, and returns its url and a working
+// copy to push further changes from.
+func createBareRemote(t *testing.T) (url string, work string) {
+	url = filepath.Join(t.TempDir(), "profiles.git")
+	work = filepath.Join(t.TempDir(), "work")
+	runGit(t, "", "init", "--quiet", "--bare", url)
+	runGit(t, "", "clone", "--quiet", url, work)
+
+	_ = os.WriteFile(filepath.Join(work, "settings.xml.base"), []byte("<settings/>"), 0644)
+	_ = os.WriteFile(filepath.Join(work, "settings.xml.child"), []byte("<settings/>"), 0644)
+	_ = os.WriteFile(filepath.Join(work, "child.extends"), []byte("base\n"), 0644)
+	_ = os.WriteFile(filepath.Join(work, "child.maven_opts"), []byte("-Xmx2g\n"), 0644)
+	commitAndPush(t, work, "Add profiles")
+	return url, work
+}
+
+func commitAndPush(t *testing.T, work string, message string) {
+	runGit(t, work, "add", "-A")
+	runGit(t, work, "-c", "user.name=menv", "-c", "user.email=menv@example.com", "commit", "--quiet", "-m", message)
+	runGit(t, work, "push", "--quiet", "origin", "HEAD")
+}
+
+func runGit(t *testing.T, dir string, args ...string) {
+	if dir != "" {
+		args = append([]string{"-C", dir}, args...)
+	}
+	out, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestRemote(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	initTest(t)
+	_ = Create("local")
+	url, work := createBareRemote(t)
+
+	assert.NoError(t, AddRemote("team", url, ExecCmdProvider))
+	assert.Equal(t, []string{"team"}, Remotes())
+	assert.Equal(t, []string{"local", "team/base", "team/child"}, Profiles())
+	assert.True(t, Exists("team/child"))
+	assert.Equal(t, "team/base", Parent("team/child"))
+	assert.Equal(t, []string{"team/child", "team/base"}, mustChain(t, "team/child"))
+	assert.Equal(t, "-Xmx2g", MvnOpts("team/child"))
+
+	assert.EqualError(t, AddRemote("team", url, ExecCmdProvider), "remote team already exists")
+	assert.EqualError(t, AddRemote("a/b", url, ExecCmdProvider), "invalid remote name a/b")
+	assert.ErrorContains(t, AddRemote("broken", filepath.Join(t.TempDir(), "missing.git"), ExecCmdProvider), "could not clone")
+	assert.NoDirExists(t, RemoteDir("broken"))
+
+	status, err := Status("team", ExecCmdProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, RemoteStatus{Name: "team", Url: url}, status)
+
+	_ = os.WriteFile(filepath.Join(work, "settings.xml.extra"), []byte("<settings/>"), 0644)
+	commitAndPush(t, work, "Add extra profile")
+
+	status, err = Status("team", ExecCmdProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, status.Behind)
+	assert.False(t, Exists("team/extra"))
+
+	assert.NoError(t, PullRemote("team", ExecCmdProvider))
+	assert.True(t, Exists("team/extra"))
+	status, _ = Status("team", ExecCmdProvider)
+	assert.Equal(t, 0, status.Behind)
+
+	assert.EqualError(t, PullRemote("unknown", ExecCmdProvider), "remote unknown does not exist")
+	assert.NoError(t, RemoveRemote("team"))
+	assert.Equal(t, []string{"local"}, Profiles())
+}
+
+func TestRemoteProfilesAreReadOnly(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	initTest(t)
+	url, _ := createBareRemote(t)
+	_ = AddRemote("team", url, ExecCmdProvider)
+
+	readOnly := "profile team/base is read-only, it is managed by remote team"
+	assert.EqualError(t, Create("team/new"), "profile team/new is read-only, it is managed by remote team")
+	assert.EqualError(t, Remove("team/base"), readOnly)
+	assert.EqualError(t, SetMvnOpts("team/base", "-Xmx1g"), readOnly)
+	assert.EqualError(t, SetEnv("team/base", "KEY", "value"), readOnly)
+	assert.EqualError(t, SetJava("team/base", "17"), readOnly)
+	assert.EqualError(t, Import("team/base", []byte("<settings/>"), "", true), readOnly)
+	assert.True(t, Exists("team/base"))
+
+	_ = Create("mine")
+	assert.NoError(t, Extend("mine", "team/child"))
+	assert.Equal(t, []string{"mine", "team/child", "team/base"}, mustChain(t, "mine"))
+}
+
+func mustChain(t *testing.T, profile string) []string {
+	chain, err := Chain(profile)
+	assert.NoError(t, err)
+	return chain
+}
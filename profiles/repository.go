@@ -0,0 +1,78 @@
+package profiles
+
+import (
+	"github.com/beevik/etree"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var propertyPattern = regexp.MustCompile(`\$\{([^}]+)}`)
+
+func isolatedFile(profile string) string {
+	dir, name := profileDir(profile)
+	return dir + "/" + name + ".isolated_repository"
+}
+
+// RepositoryDir returns the isolated local repository of the given profile.
+func RepositoryDir(profile string) string {
+	return filepath.Join(cfg.MenvRoot, "repositories", profile)
+}
+
+// Isolated reports whether the given profile uses its own local repository instead of the shared one.
+func Isolated(profile string) bool {
+	_, err := os.Stat(isolatedFile(profile))
+	return err == nil
+}
+
+// SetIsolated turns the isolated local repository of the given profile on or off. Turning it off keeps the content
+// of the isolated repository.
+func SetIsolated(profile string, isolated bool) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+
+	if !isolated {
+		_ = os.Remove(isolatedFile(profile))
+		return nil
+	}
+
+	if err := os.MkdirAll(RepositoryDir(profile), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(isolatedFile(profile), []byte{}, 0644)
+}
+
+// SettingsLocalRepository returns the <localRepository> declared in the given settings file with ${user.home} and
+// ${env.VAR} expanded, or an empty string if it declares none.
+func SettingsLocalRepository(file string) string {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromFile(file); err != nil || doc.Root() == nil {
+		return ""
+	}
+
+	element := doc.Root().SelectElement("localRepository")
+	if element == nil {
+		return ""
+	}
+
+	home, _ := os.UserHomeDir()
+	return propertyPattern.ReplaceAllStringFunc(strings.TrimSpace(element.Text()), func(match string) string {
+		name := match[2 : len(match)-1]
+		switch {
+		case name == "user.home":
+			return home
+		case strings.HasPrefix(name, "env."):
+			return os.Getenv(strings.TrimPrefix(name, "env."))
+		default:
+			return match
+		}
+	})
+}
+
+// DefaultRepositoryDir returns the local repository maven uses when no <localRepository> is declared.
+func DefaultRepositoryDir() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".m2", "repository")
+}
@@ -0,0 +1,41 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetIsolated(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+
+	assert.False(t, Isolated("test"))
+	assert.NoError(t, SetIsolated("test", true))
+	assert.True(t, Isolated("test"))
+	assert.DirExists(t, RepositoryDir("test"))
+	assert.Equal(t, filepath.Join(cfg.MenvRoot, "repositories", "test"), RepositoryDir("test"))
+
+	assert.NoError(t, SetIsolated("test", false))
+	assert.False(t, Isolated("test"))
+	assert.DirExists(t, RepositoryDir("test"))
+
+	assert.EqualError(t, SetIsolated("unknown", true), "profile unknown does not exist")
+}
+
+func TestSettingsLocalRepository(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+	home, _ := os.UserHomeDir()
+	t.Setenv("REPO_ROOT", "/data")
+
+	assert.Empty(t, SettingsLocalRepository(File("test")))
+	assert.Empty(t, SettingsLocalRepository(filepath.Join(cfg.MenvRoot, "missing.xml")))
+
+	_ = os.WriteFile(File("test"), []byte("<settings><localRepository>${user.home}/.m2/acme</localRepository></settings>"), 0644)
+	assert.Equal(t, home+"/.m2/acme", SettingsLocalRepository(File("test")))
+
+	_ = os.WriteFile(File("test"), []byte("<settings><localRepository> ${env.REPO_ROOT}/m2 </localRepository></settings>"), 0644)
+	assert.Equal(t, "/data/m2", SettingsLocalRepository(File("test")))
+}
@@ -0,0 +1,241 @@
+package profiles
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"menv/config"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// SecretPrefix marks a reference to a secret in a settings.xml, like menv-secret:nexus-token.
+const SecretPrefix = "menv-secret:"
+
+var secretNamePattern = regexp.MustCompile(`^[A-Za-z0-9._/-]+$`)
+var secretReference = regexp.MustCompile(regexp.QuoteMeta(SecretPrefix) + `([A-Za-z0-9._/-]+)`)
+
+func SecretsFile() string {
+	return filepath.Join(cfg.MenvRoot, "secrets.enc")
+}
+
+func SecretsKeyFile() string {
+	return filepath.Join(cfg.MenvRoot, "secrets.key")
+}
+
+// Secrets returns the names of the secrets in the local secrets file, in alphabetical order.
+func Secrets() ([]string, error) {
+	secrets, err := readSecrets()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// SetSecret stores the given secret in the local secrets file.
+func SetSecret(name string, value string) error {
+	if !secretNamePattern.MatchString(name) {
+		return errors.New(fmt.Sprintf("invalid secret name %v, use letters, digits, '.', '_', '-' and '/'", name))
+	}
+
+	secrets, err := readSecrets()
+	if err != nil {
+		return err
+	}
+	secrets[name] = value
+	return writeSecrets(secrets)
+}
+
+// RemoveSecret removes the given secret from the local secrets file.
+func RemoveSecret(name string) error {
+	secrets, err := readSecrets()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[name]; !ok {
+		return errors.New(fmt.Sprintf("secret %v does not exist", name))
+	}
+	delete(secrets, name)
+	return writeSecrets(secrets)
+}
+
+// LookupSecret returns the value of the given secret from the local secrets file or, when it is not found there, from
+// the configured secret command.
+func LookupSecret(name string, shell func(string, ...string) ShellCommand) (string, error) {
+	secrets, err := readSecrets()
+	if err != nil {
+		return "", err
+	}
+	if value, ok := secrets[name]; ok {
+		return value, nil
+	}
+
+	command := config.SecretCommand()
+	if command == "" {
+		return "", errors.New(fmt.Sprintf("secret %v does not exist, run 'menv secret set %v' or configure secret_command", name, name))
+	}
+
+	// the name is passed as a positional parameter, so it is never interpreted by the shell, and {name} is replaced by
+	// the bare parameter, so it can be used within quotes as well
+	if strings.Contains(command, "{name}") {
+		command = strings.ReplaceAll(command, "{name}", "$1")
+	} else {
+		command += ` "$1"`
+	}
+	cmd := shell("sh", "-c", command, "menv-secret", name)
+	var stderr strings.Builder
+	cmd.Stderr(&stderr)
+	out, err := cmd.Output()
+	if err != nil {
+		return "", errors.New(fmt.Sprintf("could not resolve secret %v: %v %v", name, err, strings.TrimSpace(stderr.String())))
+	}
+
+	// like pass, commands print the secret on the first line
+	value, _, _ := strings.Cut(strings.TrimRight(string(out), "\r\n"), "\n")
+	return strings.TrimRight(value, "\r"), nil
+}
+
+// HasSecretReferences reports whether the given settings contain references to secrets.
+func HasSecretReferences(data []byte) bool {
+	return secretReference.Match(data)
+}
+
+// ResolveSecrets replaces the secret references in the given settings by their values.
+func ResolveSecrets(data []byte, shell func(string, ...string) ShellCommand) ([]byte, error) {
+	resolved := make(map[string]string)
+	for _, match := range secretReference.FindAllSubmatch(data, -1) {
+		name := string(match[1])
+		if _, ok := resolved[name]; ok {
+			continue
+		}
+		value, err := LookupSecret(name, shell)
+		if err != nil {
+			return nil, err
+		}
+		resolved[name] = escapeXml(value)
+	}
+
+	return secretReference.ReplaceAllFunc(data, func(reference []byte) []byte {
+		return []byte(resolved[strings.TrimPrefix(string(reference), SecretPrefix)])
+	}), nil
+}
+
+// ResolvedSettingsFile returns the settings file maven should use for the given profile, like SettingsFile, with its
+// secret references resolved. When the settings contain references, the result is a temporary file readable only by
+// the current user, which is removed by calling the returned cleanup function.
+func ResolvedSettingsFile(profile string, shell func(string, ...string) ShellCommand) (string, func(), error) {
+	file, cleanup, err := SettingsFile(profile)
+	if err != nil {
+		return "", func() {}, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		cleanup()
+		return "", func() {}, err
+	}
+	if !HasSecretReferences(data) {
+		return file, cleanup, nil
+	}
+
+	resolved, err := ResolveSecrets(data, shell)
+	cleanup()
+	if err != nil {
+		return "", func() {}, err
+	}
+	return temporarySettings(resolved)
+}
+
+func readSecrets() (map[string]string, error) {
+	secrets := make(map[string]string)
+	data, err := os.ReadFile(SecretsFile())
+	if os.IsNotExist(err) {
+		return secrets, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := os.ReadFile(SecretsKeyFile())
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("could not read the key of the secrets file: %v", err))
+	}
+	plain, err := openSealed(key, data)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("could not decrypt %v: %v", SecretsFile(), err))
+	}
+	if err := yaml.Unmarshal(plain, &secrets); err != nil {
+		return nil, errors.New(fmt.Sprintf("could not read %v: %v", SecretsFile(), err))
+	}
+	return secrets, nil
+}
+
+func writeSecrets(secrets map[string]string) error {
+	key, err := os.ReadFile(SecretsKeyFile())
+	if os.IsNotExist(err) {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return err
+		}
+		if err := os.WriteFile(SecretsKeyFile(), key, 0600); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	plain, err := yaml.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	sealed, err := seal(key, plain)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(SecretsFile(), sealed, 0600)
+}
+
+// seal encrypts the given data with AES-GCM, the result starts with the random nonce.
+func seal(key []byte, data []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// openSealed decrypts data encrypted by seal.
+func openSealed(key []byte, data []byte) ([]byte, error) {
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, errors.New("data is too short")
+	}
+	return aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], nil)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
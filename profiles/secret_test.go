@@ -0,0 +1,87 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"menv/config"
+	"os"
+	"testing"
+)
+
+func TestSecrets(t *testing.T) {
+	initTest(t)
+
+	names, err := Secrets()
+	assert.NoError(t, err)
+	assert.Empty(t, names)
+
+	assert.NoError(t, SetSecret("nexus-token", "s3cret"))
+	assert.NoError(t, SetSecret("team/deploy", "other"))
+	assert.EqualError(t, SetSecret("with space", "x"), "invalid secret name with space, use letters, digits, '.', '_', '-' and '/'")
+
+	names, _ = Secrets()
+	assert.Equal(t, []string{"nexus-token", "team/deploy"}, names)
+
+	data, _ := os.ReadFile(SecretsFile())
+	assert.NotContains(t, string(data), "s3cret")
+	info, _ := os.Stat(SecretsKeyFile())
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	value, err := LookupSecret("nexus-token", ExecCmdProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, "s3cret", value)
+
+	assert.NoError(t, RemoveSecret("nexus-token"))
+	assert.EqualError(t, RemoveSecret("nexus-token"), "secret nexus-token does not exist")
+	_, err = LookupSecret("nexus-token", ExecCmdProvider)
+	assert.EqualError(t, err, "secret nexus-token does not exist, run 'menv secret set nexus-token' or configure secret_command")
+}
+
+func TestLookupSecretWithCommand(t *testing.T) {
+	initTest(t)
+	config.Set(config.Config{MenvRoot: config.Get().MenvRoot, SecretCommand: "printf '%s-value\\nsecond line\\n' {name}"})
+
+	value, err := LookupSecret("nexus-token", ExecCmdProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, "nexus-token-value", value)
+
+	config.Set(config.Config{MenvRoot: config.Get().MenvRoot, SecretCommand: `printf '%s\n' "secret/{name}"`})
+	value, err = LookupSecret("nexus-token", ExecCmdProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret/nexus-token", value)
+
+	config.Set(config.Config{MenvRoot: config.Get().MenvRoot, SecretCommand: "echo"})
+	value, _ = LookupSecret("$(id)", ExecCmdProvider)
+	assert.Equal(t, "$(id)", value)
+
+	config.Set(config.Config{MenvRoot: config.Get().MenvRoot, SecretCommand: "exit 3"})
+	_, err = LookupSecret("nexus-token", ExecCmdProvider)
+	assert.ErrorContains(t, err, "could not resolve secret nexus-token")
+}
+
+func TestResolvedSettingsFile(t *testing.T) {
+	initTest(t)
+	_ = Create("plain")
+	_ = Create("test")
+	_ = os.WriteFile(File("test"), []byte("<settings><servers><server><id>nexus</id><password>menv-secret:nexus-token</password></server></servers></settings>"), 0644)
+
+	file, cleanup, err := ResolvedSettingsFile("plain", ExecCmdProvider)
+	assert.NoError(t, err)
+	assert.Equal(t, File("plain"), file)
+	cleanup()
+
+	_, _, err = ResolvedSettingsFile("test", ExecCmdProvider)
+	assert.EqualError(t, err, "secret nexus-token does not exist, run 'menv secret set nexus-token' or configure secret_command")
+
+	_ = SetSecret("nexus-token", "a<b")
+	file, cleanup, err = ResolvedSettingsFile("test", ExecCmdProvider)
+	assert.NoError(t, err)
+	assert.NotEqual(t, File("test"), file)
+
+	data, _ := os.ReadFile(file)
+	assert.Contains(t, string(data), "<password>a&lt;b</password>")
+	info, _ := os.Stat(file)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	cleanup()
+	assert.NoFileExists(t, file)
+}
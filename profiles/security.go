@@ -0,0 +1,174 @@
+package profiles
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/beevik/etree"
+	"os"
+	"strings"
+)
+
+// masterPasswordKey is the key maven uses to encrypt the master password in settings-security.xml.
+const masterPasswordKey = "settings.security"
+
+const (
+	saltSize  = 8
+	chunkSize = 16
+)
+
+func SecurityFile(profile string) string {
+	dir, name := profileDir(profile)
+	return dir + "/settings-security.xml." + name
+}
+
+func SecurityExists(profile string) bool {
+	_, err := os.Stat(SecurityFile(profile))
+	return !os.IsNotExist(err)
+}
+
+// Security returns the settings-security.xml of the given profile or of the nearest profile it extends, or an empty
+// string if none of them has one.
+func Security(profile string) string {
+	chain, err := Chain(profile)
+	if err != nil {
+		return ""
+	}
+
+	for _, p := range chain {
+		if SecurityExists(p) {
+			return SecurityFile(p)
+		}
+	}
+	return ""
+}
+
+// InitSecurity creates the settings-security.xml of the given profile with the given master password. An existing
+// master password is only replaced when force is true, because the passwords encrypted with it can no longer be
+// decrypted.
+func InitSecurity(profile string, master string, force bool) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	if SecurityExists(profile) && !force {
+		return errors.New(fmt.Sprintf("profile %v already has a master password, use --force to replace it", profile))
+	}
+
+	encrypted, err := EncryptPassword(master, masterPasswordKey)
+	if err != nil {
+		return err
+	}
+
+	doc := etree.NewDocument()
+	doc.CreateProcInst("xml", `version="1.0" encoding="UTF-8"`)
+	doc.CreateElement("settingsSecurity").CreateElement("master").SetText(encrypted)
+	doc.Indent(2)
+	content, err := doc.WriteToBytes()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(SecurityFile(profile), content, 0600)
+}
+
+// MasterPassword returns the decrypted master password of the given profile.
+func MasterPassword(profile string) (string, error) {
+	file := Security(profile)
+	if file == "" {
+		return "", errors.New(fmt.Sprintf("profile %v has no master password, run 'menv security init %v'", profile, profile))
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromFile(file); err != nil {
+		return "", errors.New(fmt.Sprintf("could not parse %v: %v", file, err))
+	}
+	master := doc.FindElement("/settingsSecurity/master")
+	if master == nil {
+		return "", errors.New(fmt.Sprintf("%v has no master password", file))
+	}
+	return DecryptPassword(strings.TrimSpace(master.Text()), masterPasswordKey)
+}
+
+// EncryptProfilePassword encrypts the given password with the master password of the given profile.
+func EncryptProfilePassword(profile string, password string) (string, error) {
+	master, err := MasterPassword(profile)
+	if err != nil {
+		return "", err
+	}
+	return EncryptPassword(password, master)
+}
+
+// EncryptPassword encrypts the given value the way maven does, using AES-128-CBC with a key and IV derived from the
+// SHA-256 of the key and a random salt. The result is wrapped in braces, like {...}.
+func EncryptPassword(value string, key string) (string, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	block, iv, err := passwordCipher(key, salt)
+	if err != nil {
+		return "", err
+	}
+	clear := []byte(value)
+	padding := aes.BlockSize - len(clear)%aes.BlockSize
+	clear = append(clear, bytes.Repeat([]byte{byte(padding)}, padding)...)
+	encrypted := make([]byte, len(clear))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(encrypted, clear)
+
+	// the encrypted bytes are prefixed with the salt and padded with random bytes to a multiple of the chunk size
+	padLength := chunkSize - (saltSize+len(encrypted)+1)%chunkSize
+	result := make([]byte, saltSize+1+len(encrypted)+padLength)
+	if _, err := rand.Read(result); err != nil {
+		return "", err
+	}
+	copy(result, salt)
+	result[saltSize] = byte(padLength)
+	copy(result[saltSize+1:], encrypted)
+
+	return "{" + base64.StdEncoding.EncodeToString(result) + "}", nil
+}
+
+// DecryptPassword decrypts a value encrypted by EncryptPassword or maven.
+func DecryptPassword(value string, key string) (string, error) {
+	invalid := errors.New("invalid encrypted password")
+
+	start := strings.Index(value, "{")
+	end := strings.LastIndex(value, "}")
+	if start < 0 || end < start {
+		return "", invalid
+	}
+	data, err := base64.StdEncoding.DecodeString(value[start+1 : end])
+	if err != nil || len(data) < saltSize+1 {
+		return "", invalid
+	}
+
+	padLength := int(data[saltSize])
+	length := len(data) - saltSize - 1 - padLength
+	if length <= 0 || length%aes.BlockSize != 0 {
+		return "", invalid
+	}
+
+	block, iv, err := passwordCipher(key, data[:saltSize])
+	if err != nil {
+		return "", err
+	}
+	clear := make([]byte, length)
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(clear, data[saltSize+1:saltSize+1+length])
+
+	padding := int(clear[len(clear)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(clear[len(clear)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return "", errors.New("could not decrypt password, the master password does not match")
+	}
+	return string(clear[:len(clear)-padding]), nil
+}
+
+func passwordCipher(key string, salt []byte) (cipher.Block, []byte, error) {
+	digest := sha256.Sum256(append([]byte(key), salt...))
+	block, err := aes.NewCipher(digest[:16])
+	return block, digest[16:], err
+}
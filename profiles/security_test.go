@@ -0,0 +1,69 @@
+package profiles
+
+import (
+	"github.com/beevik/etree"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"regexp"
+	"testing"
+)
+
+func TestEncryptPassword(t *testing.T) {
+	encrypted, err := EncryptPassword("my-password", "master")
+	assert.NoError(t, err)
+	assert.Regexp(t, regexp.MustCompile(`^\{[A-Za-z0-9+/]+=*}$`), encrypted)
+
+	other, _ := EncryptPassword("my-password", "master")
+	assert.NotEqual(t, encrypted, other)
+
+	decrypted, err := DecryptPassword(encrypted, "master")
+	assert.NoError(t, err)
+	assert.Equal(t, "my-password", decrypted)
+
+	_, err = DecryptPassword(encrypted, "wrong")
+	assert.Error(t, err)
+	_, err = DecryptPassword("{not base64}", "master")
+	assert.EqualError(t, err, "invalid encrypted password")
+}
+
+func TestEncryptPasswordLengths(t *testing.T) {
+	for _, value := range []string{"", "a", "exactly16bytes!!", "a password longer than a single block of aes"} {
+		encrypted, err := EncryptPassword(value, "master")
+		assert.NoError(t, err)
+		decrypted, err := DecryptPassword(encrypted, "master")
+		assert.NoError(t, err)
+		assert.Equal(t, value, decrypted)
+	}
+}
+
+func TestInitSecurity(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = Extend("child", "base")
+
+	_, err := MasterPassword("child")
+	assert.EqualError(t, err, "profile child has no master password, run 'menv security init child'")
+	assert.Empty(t, Security("child"))
+
+	assert.NoError(t, InitSecurity("base", "master", false))
+	assert.EqualError(t, InitSecurity("base", "other", false), "profile base already has a master password, use --force to replace it")
+	assert.Equal(t, SecurityFile("base"), Security("child"))
+
+	info, _ := os.Stat(SecurityFile("base"))
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	doc := etree.NewDocument()
+	assert.NoError(t, doc.ReadFromFile(SecurityFile("base")))
+	master := doc.FindElement("/settingsSecurity/master").Text()
+	decrypted, _ := DecryptPassword(master, "settings.security")
+	assert.Equal(t, "master", decrypted)
+
+	encrypted, err := EncryptProfilePassword("child", "secret")
+	assert.NoError(t, err)
+	decrypted, _ = DecryptPassword(encrypted, "master")
+	assert.Equal(t, "secret", decrypted)
+
+	assert.NoError(t, Remove("child"))
+	assert.NoError(t, Remove("base"))
+	assert.NoFileExists(t, SecurityFile("base"))
+}
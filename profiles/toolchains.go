@@ -0,0 +1,56 @@
+package profiles
+
+import (
+	"os"
+)
+
+const toolchainsTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<toolchains xmlns="http://maven.apache.org/TOOLCHAINS/1.1.0"
+            xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance"
+            xsi:schemaLocation="http://maven.apache.org/TOOLCHAINS/1.1.0 http://maven.apache.org/xsd/toolchains-1.1.0.xsd">
+</toolchains>
+`
+
+func ToolchainsFile(profile string) string {
+	dir, name := profileDir(profile)
+	return dir + "/toolchains.xml." + name
+}
+
+func ToolchainsExists(profile string) bool {
+	_, err := os.Stat(ToolchainsFile(profile))
+	return !os.IsNotExist(err)
+}
+
+// Toolchains returns the toolchains file of the given profile or of the nearest profile it extends, or an empty
+// string if none of them has one.
+func Toolchains(profile string) string {
+	chain, err := Chain(profile)
+	if err != nil {
+		return ""
+	}
+
+	for _, p := range chain {
+		if ToolchainsExists(p) {
+			return ToolchainsFile(p)
+		}
+	}
+	return ""
+}
+
+// WriteToolchains writes the given toolchains.xml content for the given profile.
+func WriteToolchains(profile string, content []byte) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	return os.WriteFile(ToolchainsFile(profile), content, 0644)
+}
+
+func EditToolchains(profile string, shell func(string, ...string) ShellCommand) error {
+	if err := writable(profile); err != nil {
+		return err
+	}
+	if !ToolchainsExists(profile) {
+		_ = os.WriteFile(ToolchainsFile(profile), []byte(toolchainsTemplate), 0644)
+	}
+	return genericEdit(profile, shell, ToolchainsFile)
+}
@@ -0,0 +1,48 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"os"
+	"testing"
+)
+
+func TestToolchains(t *testing.T) {
+	initTest(t)
+	_ = Create("base")
+	_ = Create("child")
+	_ = Extend("child", "base")
+
+	assert.Empty(t, Toolchains("child"))
+	assert.NoError(t, WriteToolchains("base", []byte(toolchainsTemplate)))
+	assert.Equal(t, ToolchainsFile("base"), Toolchains("child"))
+	assert.NoError(t, WriteToolchains("child", []byte(toolchainsTemplate)))
+	assert.Equal(t, ToolchainsFile("child"), Toolchains("child"))
+
+	assert.EqualError(t, WriteToolchains("unknown", []byte{}), "profile unknown does not exist")
+}
+
+func TestEditToolchains(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+
+	mockProvider := func(string, ...string) ShellCommand {
+		return &mockShell
+	}
+
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Return(nil)
+	assert.NoError(t, EditToolchains("test", mockProvider))
+	mockShell.AssertExpectations(t)
+
+	data, _ := os.ReadFile(ToolchainsFile("test"))
+	assert.Equal(t, toolchainsTemplate, string(data))
+
+	assert.EqualError(t, EditToolchains("unknown", mockProvider), "profile unknown does not exist")
+}
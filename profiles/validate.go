@@ -0,0 +1,209 @@
+package profiles
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// settingsNamespaces are the supported namespaces of the settings.xml root element.
+var settingsNamespaces = []string{
+	"http://maven.apache.org/SETTINGS/1.0.0",
+	"http://maven.apache.org/SETTINGS/1.1.0",
+	"http://maven.apache.org/SETTINGS/1.2.0",
+}
+
+var repositoryElements = []string{"id", "name", "url", "layout", "releases", "snapshots"}
+var repositoryPolicyElements = []string{"enabled", "updatePolicy", "checksumPolicy"}
+
+// settingsElements describes the structure of the settings 1.0.0 to 1.2.0 models: the child elements allowed for an
+// element, by the path of the element. The children of elements mapped to nil are not checked.
+var settingsElements = map[string][]string{
+	"settings": {"localRepository", "interactiveMode", "usePluginRegistry", "offline", "proxies", "servers", "mirrors",
+		"profiles", "activeProfiles", "pluginGroups"},
+
+	"settings/proxies":       {"proxy"},
+	"settings/proxies/proxy": {"id", "active", "protocol", "username", "password", "port", "host", "nonProxyHosts"},
+
+	"settings/servers": {"server"},
+	"settings/servers/server": {"id", "username", "password", "privateKey", "passphrase", "filePermissions",
+		"directoryPermissions", "configuration"},
+	"settings/servers/server/configuration": nil,
+
+	"settings/mirrors":        {"mirror"},
+	"settings/mirrors/mirror": {"id", "name", "url", "layout", "mirrorOf", "mirrorOfLayouts", "blocked"},
+
+	"settings/profiles":                                                       {"profile"},
+	"settings/profiles/profile":                                               {"id", "activation", "properties", "repositories", "pluginRepositories"},
+	"settings/profiles/profile/activation":                                    {"activeByDefault", "jdk", "os", "property", "file"},
+	"settings/profiles/profile/activation/os":                                 {"name", "family", "arch", "version"},
+	"settings/profiles/profile/activation/property":                           {"name", "value"},
+	"settings/profiles/profile/activation/file":                               {"missing", "exists"},
+	"settings/profiles/profile/properties":                                    nil,
+	"settings/profiles/profile/repositories":                                  {"repository"},
+	"settings/profiles/profile/repositories/repository":                       repositoryElements,
+	"settings/profiles/profile/repositories/repository/releases":              repositoryPolicyElements,
+	"settings/profiles/profile/repositories/repository/snapshots":             repositoryPolicyElements,
+	"settings/profiles/profile/pluginRepositories":                            {"pluginRepository"},
+	"settings/profiles/profile/pluginRepositories/pluginRepository":           repositoryElements,
+	"settings/profiles/profile/pluginRepositories/pluginRepository/releases":  repositoryPolicyElements,
+	"settings/profiles/profile/pluginRepositories/pluginRepository/snapshots": repositoryPolicyElements,
+
+	"settings/activeProfiles": {"activeProfile"},
+	"settings/pluginGroups":   {"pluginGroup"},
+}
+
+// identifiedElements are the elements that require a unique id within their parent.
+var identifiedElements = []string{
+	"settings/servers/server",
+	"settings/mirrors/mirror",
+	"settings/profiles/profile",
+	"settings/profiles/profile/repositories/repository",
+	"settings/profiles/profile/pluginRepositories/pluginRepository",
+}
+
+// EditAction is the choice made when an edited settings.xml is invalid.
+type EditAction int
+
+const (
+	EditKeep EditAction = iota
+	EditReopen
+	EditRevert
+)
+
+// ValidationError is a problem found in a settings.xml, with the position it was found at.
+type ValidationError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e ValidationError) Error() string {
+	return fmt.Sprintf("line %v, column %v: %v", e.Line, e.Column, e.Message)
+}
+
+// ValidateSettings checks that the given data is a well-formed maven settings document.
+func ValidateSettings(data []byte) error {
+	problems := CheckSettings(data)
+	if len(problems) > 0 {
+		return errors.New(fmt.Sprintf("invalid settings.xml: %v", problems[0]))
+	}
+	return nil
+}
+
+// CheckSettings parses the given settings.xml and checks it against the structure of the settings 1.0.0 to 1.2.0
+// models: only known elements are used, the elements that require an id have one, and ids are unique. A syntax error
+// stops the check, so it is the last problem reported.
+func CheckSettings(data []byte) []ValidationError {
+	problems := make([]ValidationError, 0)
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+
+	type element struct {
+		path   string
+		tag    string
+		line   int
+		column int
+		// unchecked is true for elements whose children are not checked, because they are free-form or unknown.
+		unchecked bool
+		id        string
+		text      strings.Builder
+		ids       map[string]bool
+	}
+	stack := make([]*element, 0)
+	root := false
+
+	for {
+		line, column := position(data, decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			line, column = decoder.InputPos()
+			message := err.Error()
+			if syntaxError, ok := err.(*xml.SyntaxError); ok {
+				message = syntaxError.Msg
+			}
+			return append(problems, ValidationError{Line: line, Column: column, Message: message})
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			current := &element{tag: t.Name.Local, line: line, column: column, ids: make(map[string]bool)}
+
+			if len(stack) == 0 {
+				if root || t.Name.Local != "settings" {
+					return append(problems, ValidationError{Line: line, Column: column, Message: "the root element must be <settings>"})
+				}
+				root = true
+				if t.Name.Space != "" && !slices.Contains(settingsNamespaces, t.Name.Space) {
+					problems = append(problems, ValidationError{Line: line, Column: column, Message: fmt.Sprintf("unsupported namespace %v", t.Name.Space)})
+				}
+				current.path = "settings"
+				stack = append(stack, current)
+				continue
+			}
+
+			parent := stack[len(stack)-1]
+			current.path = parent.path + "/" + t.Name.Local
+			switch {
+			case parent.unchecked:
+				current.path = ""
+				current.unchecked = true
+			case !slices.Contains(settingsElements[parent.path], t.Name.Local):
+				problems = append(problems, ValidationError{Line: line, Column: column, Message: fmt.Sprintf("unknown element <%v> in <%v>", t.Name.Local, parent.tag)})
+				current.unchecked = true
+			default:
+				children, known := settingsElements[current.path]
+				current.unchecked = known && children == nil
+			}
+			stack = append(stack, current)
+		case xml.EndElement:
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if len(stack) == 0 || current.path == "" {
+				continue
+			}
+			parent := stack[len(stack)-1]
+
+			if current.tag == "id" {
+				parent.id = strings.TrimSpace(current.text.String())
+			}
+			if !slices.Contains(identifiedElements, current.path) {
+				continue
+			}
+			switch {
+			case current.id == "":
+				problems = append(problems, ValidationError{Line: current.line, Column: current.column, Message: fmt.Sprintf("<%v> has no <id>", current.tag)})
+			case parent.ids[current.id]:
+				problems = append(problems, ValidationError{Line: current.line, Column: current.column, Message: fmt.Sprintf("duplicate %v id %v", current.tag, current.id)})
+			default:
+				parent.ids[current.id] = true
+			}
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		}
+	}
+
+	if !root {
+		return append(problems, ValidationError{Line: 1, Column: 1, Message: "the root element must be <settings>"})
+	}
+	return problems
+}
+
+// position returns the line and column of the given byte offset in data.
+func position(data []byte, offset int64) (int, int) {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	before := data[:offset]
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := int(offset) - bytes.LastIndexByte(before, '\n')
+	return line, column
+}
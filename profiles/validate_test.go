@@ -0,0 +1,112 @@
+package profiles
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"os"
+	"testing"
+)
+
+func TestCheckSettings(t *testing.T) {
+	settings := `<settings xmlns="http://maven.apache.org/SETTINGS/1.2.0">
+  <servers>
+    <server>
+      <id>nexus</id>
+      <configuration><anything><id>free-form</id></anything></configuration>
+    </server>
+    <server>
+      <id>nexus</id>
+    </server>
+    <server>
+      <username>anonymous</username>
+    </server>
+  </servers>
+  <mirrors>
+    <mirror><id>central</id><mirrorof>*</mirrorof></mirror>
+  </mirrors>
+  <profiles>
+    <profile>
+      <id>test</id>
+      <properties><any.property>value</any.property></properties>
+      <repositories>
+        <repository><id>releases</id><snapshots><enabled>false</enabled></snapshots></repository>
+      </repositories>
+    </profile>
+  </profiles>
+</settings>
+`
+
+	assert.Equal(t, []ValidationError{
+		{Line: 7, Column: 5, Message: "duplicate server id nexus"},
+		{Line: 10, Column: 5, Message: "<server> has no <id>"},
+		{Line: 15, Column: 29, Message: "unknown element <mirrorof> in <mirror>"},
+	}, CheckSettings([]byte(settings)))
+
+	assert.Empty(t, CheckSettings([]byte(template)))
+}
+
+func TestCheckSettingsSyntaxError(t *testing.T) {
+	problems := CheckSettings([]byte("<settings>\n  <servers>\n  </server>\n</settings>\n"))
+	assert.Len(t, problems, 1)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Contains(t, problems[0].Message, "element <servers> closed by </server>")
+
+	assert.Equal(t, []ValidationError{{Line: 1, Column: 1, Message: "the root element must be <settings>"}}, CheckSettings([]byte("<project/>")))
+	assert.Equal(t, []ValidationError{{Line: 1, Column: 1, Message: "unsupported namespace urn:other"}}, CheckSettings([]byte(`<settings xmlns="urn:other"/>`)))
+}
+
+func TestEditInvalidSettings(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+	original, _ := os.ReadFile(File("test"))
+	invalid := "<settings><servers></settings>"
+
+	tests := []struct {
+		action   EditAction
+		expected string
+	}{
+		{EditKeep, invalid},
+		{EditRevert, string(original)},
+	}
+
+	for _, test := range tests {
+		_ = os.WriteFile(File("test"), original, 0644)
+		asked := 0
+		err := Edit("test", editingProvider(File("test"), invalid), func(problems []ValidationError) EditAction {
+			asked++
+			assert.NotEmpty(t, problems)
+			return test.action
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, asked)
+		data, _ := os.ReadFile(File("test"))
+		assert.Equal(t, test.expected, string(data))
+	}
+}
+
+func TestEditReopensInvalidSettings(t *testing.T) {
+	initTest(t)
+	_ = Create("test")
+
+	contents := []string{"<settings><unknown/></settings>", "<settings/>"}
+	mockShell := MockShellCommand{
+		Mock: &mock.Mock{},
+	}
+	mockShell.On("Stdin", os.Stdin).Return()
+	mockShell.On("Stdout", os.Stdout).Return()
+	mockShell.On("Stderr", os.Stderr).Return()
+	mockShell.On("Run").Run(func(mock.Arguments) {
+		_ = os.WriteFile(File("test"), []byte(contents[0]), 0644)
+		contents = contents[1:]
+	}).Return(nil)
+
+	err := Edit("test", func(string, ...string) ShellCommand { return &mockShell }, func([]ValidationError) EditAction {
+		return EditReopen
+	})
+
+	assert.NoError(t, err)
+	mockShell.AssertNumberOfCalls(t, "Run", 2)
+	data, _ := os.ReadFile(File("test"))
+	assert.Equal(t, "<settings/>", string(data))
+}